@@ -0,0 +1,354 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// toTime converts a time.Time or a date string formatted as 2006-01-02
+func toTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		if t, err := time.Parse("2006-01-02", v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// age returns the number of full years between birth and now. Birth dates
+// are calendar dates, their year, month and day are compared to the ones of
+// now in its own location without converting birth to it
+func age(birth time.Time, now time.Time) int {
+	years := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+		years--
+	}
+	return years
+}
+
+// MinAge returns a constraint validating that a birth date, given as a
+// time.Time or a 2006-01-02 string, corresponds to an age of min years or more
+func MinAge(min int) Constraint {
+	return &ageConstraint{min: min, max: -1}
+}
+
+// MaxAge returns a constraint validating that a birth date, given as a
+// time.Time or a 2006-01-02 string, corresponds to an age of max years or less
+func MaxAge(max int) Constraint {
+	return &ageConstraint{min: -1, max: max}
+}
+
+type ageConstraint struct {
+	min int
+	max int
+}
+
+// Validate returns an error if the constraint is violated
+func (c *ageConstraint) Validate(value interface{}) error {
+	return c.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext returns an error if the constraint is violated
+func (c *ageConstraint) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	birth, ok := toTime(value)
+	if !ok {
+		return NewError(ErrInvalidDate, DateMessage)
+	}
+	years := age(birth, ctx.Clock().Now())
+	if c.min >= 0 && years < c.min {
+		return NewError(ErrTooYoung, MinAgeMessage, c.min)
+	}
+	if c.max >= 0 && years > c.max {
+		return NewError(ErrTooOld, MaxAgeMessage, c.max)
+	}
+	return nil
+}
+
+// compared returns value, a time.Time or a 2006-01-02 date string, and the
+// time it is compared to: now, or the start of today for dates. Like birth
+// dates, dates are compared to the date of now in its own location
+func compared(value interface{}, now time.Time) (time.Time, time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, now, nil
+	case string:
+		date, ok := toTime(v)
+		if !ok {
+			return time.Time{}, time.Time{}, NewError(ErrInvalidDate, DateMessage)
+		}
+		year, month, day := now.Date()
+		return date, time.Date(year, month, day, 0, 0, 0, 0, date.Location()), nil
+	}
+	return time.Time{}, time.Time{}, NewError(ErrNotTime, ErrorNotTimeMessage)
+}
+
+// NotInPast returns a constraint validating that a time is now or later, or
+// that a 2006-01-02 date string is today or later
+func NotInPast() Constraint {
+	return &notInPast{}
+}
+
+type notInPast struct{}
+
+// Validate returns an error if the constraint is violated
+func (c *notInPast) Validate(value interface{}) error {
+	return c.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext returns an error if the constraint is violated
+func (c *notInPast) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	t, now, err := compared(value, ctx.Clock().Now())
+	if err != nil {
+		return err
+	}
+	if t.Before(now) {
+		return NewError(ErrInPast, NotInPastMessage)
+	}
+	return nil
+}
+
+// NotInFuture returns a constraint validating that a time is now or earlier,
+// or that a 2006-01-02 date string is today or earlier
+func NotInFuture() Constraint {
+	return &notInFuture{}
+}
+
+type notInFuture struct{}
+
+// Validate returns an error if the constraint is violated
+func (c *notInFuture) Validate(value interface{}) error {
+	return c.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext returns an error if the constraint is violated
+func (c *notInFuture) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	t, now, err := compared(value, ctx.Clock().Now())
+	if err != nil {
+		return err
+	}
+	if t.After(now) {
+		return NewError(ErrInFuture, NotInFutureMessage)
+	}
+	return nil
+}
+
+// Duration returns a constraint validating a time.Duration, or a string
+// parsed by time.ParseDuration, is between min and max
+func Duration(min, max time.Duration) Constraint {
+	return &duration{min, max}
+}
+
+type duration struct {
+	min time.Duration
+	max time.Duration
+}
+
+// Validate returns an error if the constraint is violated
+func (c *duration) Validate(value interface{}) error {
+	var d time.Duration
+	switch v := value.(type) {
+	case time.Duration:
+		d = v
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return NewError(ErrInvalidDuration, DurationMessage)
+		}
+		d = parsed
+	default:
+		return NewError(ErrInvalidDuration, DurationMessage)
+	}
+	if d < c.min {
+		return NewError(ErrDurationTooShort, DurationMinMessage, c.min.String())
+	}
+	if d > c.max {
+		return NewError(ErrDurationTooLong, DurationMaxMessage, c.max.String())
+	}
+	return nil
+}
+
+// HolidayCalendar tells whether a day is a holiday
+type HolidayCalendar interface {
+	IsHoliday(day time.Time) bool
+}
+
+// BusinessDay returns a constraint validating that a time.Time, or a
+// 2006-01-02 string, is a week day that isn't a holiday of calendar.
+// calendar may be nil
+func BusinessDay(calendar HolidayCalendar) Constraint {
+	return &businessDay{calendar}
+}
+
+type businessDay struct {
+	calendar HolidayCalendar
+}
+
+// Validate returns an error if the constraint is violated
+func (c *businessDay) Validate(value interface{}) error {
+	day, ok := toTime(value)
+	if !ok {
+		return NewError(ErrInvalidDate, DateMessage)
+	}
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return NewError(ErrNotBusinessDay, BusinessDayMessage)
+	}
+	if c.calendar != nil && c.calendar.IsHoliday(day) {
+		return NewError(ErrNotBusinessDay, BusinessDayMessage)
+	}
+	return nil
+}
+
+// NewHolidays returns an empty holiday calendar
+func NewHolidays() *Holidays {
+	return &Holidays{days: map[string]string{}}
+}
+
+// Holidays is a HolidayCalendar holding a set of days
+type Holidays struct {
+	days map[string]string
+}
+
+// Add adds a holiday named name
+func (h *Holidays) Add(day time.Time, name string) *Holidays {
+	h.days[day.Format("2006-01-02")] = name
+	return h
+}
+
+// Name returns the name of the holiday and true if day is a holiday
+func (h Holidays) Name(day time.Time) (string, bool) {
+	name, ok := h.days[day.Format("2006-01-02")]
+	return name, ok
+}
+
+// IsHoliday returns true if day is a holiday
+func (h Holidays) IsHoliday(day time.Time) bool {
+	_, ok := h.Name(day)
+	return ok
+}
+
+// Len returns the number of holidays
+func (h Holidays) Len() int {
+	return len(h.days)
+}
+
+// LoadHolidaysFile loads holidays from an iCalendar (.ics) or a JSON file
+func LoadHolidaysFile(path string) (*Holidays, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ics", ".ical", ".ifb", ".icalendar":
+		return LoadHolidaysICal(file)
+	case ".json":
+		return LoadHolidaysJSON(file)
+	}
+	return nil, fmt.Errorf("constraint: unsupported holiday file %s", path)
+}
+
+// LoadHolidaysJSON loads holidays from a JSON array of dates formatted as
+// 2006-01-02, or of objects with a date and a name property
+func LoadHolidaysJSON(reader io.Reader) (*Holidays, error) {
+	entries := []json.RawMessage{}
+	if err := json.NewDecoder(reader).Decode(&entries); err != nil {
+		return nil, err
+	}
+	holidays := NewHolidays()
+	for _, entry := range entries {
+		holiday := struct {
+			Date string `json:"date"`
+			Name string `json:"name"`
+		}{}
+		if err := json.Unmarshal(entry, &holiday.Date); err != nil {
+			if err := json.Unmarshal(entry, &holiday); err != nil {
+				return nil, err
+			}
+		}
+		day, err := time.Parse("2006-01-02", holiday.Date)
+		if err != nil {
+			return nil, err
+		}
+		holidays.Add(day, holiday.Name)
+	}
+	return holidays, nil
+}
+
+// LoadHolidaysICal loads holidays from the events of an iCalendar
+// document, each day between the start and end of an event being a holiday
+func LoadHolidaysICal(reader io.Reader) (*Holidays, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		// folded lines start with a space or a tab
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	holidays := NewHolidays()
+	var start, end time.Time
+	var name string
+	inEvent := false
+	for _, line := range lines {
+		property, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		property, _, _ = strings.Cut(strings.ToUpper(property), ";")
+		switch {
+		case property == "BEGIN" && value == "VEVENT":
+			inEvent, start, end, name = true, time.Time{}, time.Time{}, ""
+		case property == "END" && value == "VEVENT":
+			if start.IsZero() {
+				return nil, errors.New("constraint: iCalendar event without DTSTART")
+			}
+			holidays.Add(start, name)
+			for day := start.AddDate(0, 0, 1); day.Before(end); day = day.AddDate(0, 0, 1) {
+				holidays.Add(day, name)
+			}
+			inEvent = false
+		case inEvent && property == "SUMMARY":
+			name = value
+		case inEvent && (property == "DTSTART" || property == "DTEND"):
+			t, err := parseICalDate(value)
+			if err != nil {
+				return nil, err
+			}
+			if property == "DTSTART" {
+				start = t
+			} else {
+				end = t
+			}
+		}
+	}
+	return holidays, nil
+}
+
+// parseICalDate parses the date of a DATE or DATE-TIME iCalendar value
+func parseICalDate(value string) (time.Time, error) {
+	if len(value) >= 8 {
+		if t, err := time.Parse("20060102", value[:8]); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("constraint: invalid iCalendar date %s", value)
+}
@@ -0,0 +1,84 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+func TestAgeAndDurations(t *testing.T) {
+	e := expect.New(t)
+	ctx := constraint.NewExecutionContext().SetClock(constraint.FixedClock(now))
+	for _, fixture := range []list{
+		{constraint.MinAge(18), "1997-06-15", true},
+		{constraint.MinAge(18), "1997-06-16", false},
+		{constraint.MinAge(18), time.Date(1980, time.March, 1, 0, 0, 0, 0, time.UTC), true},
+		{constraint.MinAge(18), "not a date", false},
+		{constraint.MaxAge(65), "1950-06-15", true},
+		{constraint.MaxAge(64), "1950-06-15", false},
+		{constraint.NotInPast(), now.Add(time.Minute), true},
+		{constraint.NotInPast(), now.Add(-time.Minute), false},
+		{constraint.NotInFuture(), now.Add(-time.Minute), true},
+		{constraint.NotInFuture(), now.AddDate(0, 0, 1), false},
+		{constraint.Duration(time.Minute, time.Hour), 30 * time.Minute, true},
+		{constraint.Duration(time.Minute, time.Hour), "90m", false},
+		{constraint.Duration(time.Minute, time.Hour), "1h", true},
+		{constraint.Duration(time.Minute, time.Hour), "30s", false},
+		{constraint.Duration(time.Minute, time.Hour), "soon", false},
+	} {
+		err := constraint.Execute(ctx, fixture[0].(constraint.Constraint), fixture[1])
+		e.Expect(err == nil).ToBe(fixture[2].(bool))
+	}
+	// birth dates aren't converted to the location of the clock
+	newYork := time.FixedZone("EDT", -4*3600)
+	ctx.SetClock(constraint.FixedClock(time.Date(2026, time.June, 14, 22, 0, 0, 0, newYork)))
+	e.Expect(constraint.Execute(ctx, constraint.MinAge(18), "2008-06-15") == nil).ToBe(false)
+	e.Expect(constraint.Execute(ctx, constraint.MinAge(18), "2008-06-14") == nil).ToBe(true)
+	e.Expect(constraint.Execute(ctx, constraint.MinAge(18), time.Date(2008, time.June, 15, 0, 0, 0, 0, time.UTC)) == nil).ToBe(false)
+}
+
+func TestNotInPastDates(t *testing.T) {
+	e := expect.New(t)
+	ctx := constraint.NewExecutionContext().SetClock(constraint.FixedClock(now))
+	today := now.Format("2006-01-02")
+	e.Expect(constraint.Execute(ctx, constraint.NotInPast(), today) == nil).ToBe(true)
+	e.Expect(constraint.Execute(ctx, constraint.NotInFuture(), today) == nil).ToBe(true)
+	e.Expect(constraint.CodeOf(constraint.Execute(ctx, constraint.NotInPast(), now.AddDate(0, 0, -1).Format("2006-01-02")))).ToBe(constraint.ErrInPast)
+	e.Expect(constraint.CodeOf(constraint.Execute(ctx, constraint.NotInFuture(), now.AddDate(0, 0, 1).Format("2006-01-02")))).ToBe(constraint.ErrInFuture)
+	e.Expect(constraint.CodeOf(constraint.Execute(ctx, constraint.NotInPast(), "tomorrow"))).ToBe(constraint.ErrInvalidDate)
+	e.Expect(constraint.CodeOf(constraint.Execute(ctx, constraint.NotInPast(), 42))).ToBe(constraint.ErrNotTime)
+	// dates are compared to the date of the clock in its own location
+	newYork := time.FixedZone("EDT", -4*3600)
+	ctx.SetClock(constraint.FixedClock(time.Date(2026, time.June, 14, 22, 0, 0, 0, newYork)))
+	e.Expect(constraint.Execute(ctx, constraint.NotInPast(), "2026-06-14") == nil).ToBe(true)
+	e.Expect(constraint.Execute(ctx, constraint.NotInFuture(), "2026-06-15") == nil).ToBe(false)
+}
+
+func TestBusinessDay(t *testing.T) {
+	e := expect.New(t)
+	ical, err := constraint.LoadHolidaysFile("testdata/holidays.ics")
+	e.Expect(err == nil).ToBe(true)
+	e.Expect(ical.Len()).ToBe(5)
+	name, _ := ical.Name(time.Date(2015, time.August, 17, 0, 0, 0, 0, time.UTC))
+	e.Expect(name).ToBe("Summer break")
+	json, err := constraint.LoadHolidaysFile("testdata/holidays.json")
+	e.Expect(err == nil).ToBe(true)
+	e.Expect(json.Len()).ToBe(2)
+	for _, fixture := range []list{
+		{constraint.BusinessDay(nil), "2015-06-15", true},
+		{constraint.BusinessDay(nil), "2015-06-13", false},
+		{constraint.BusinessDay(ical), "2015-12-24", true},
+		{constraint.BusinessDay(ical), "2015-12-25", false},
+		{constraint.BusinessDay(ical), "2015-08-17", false},
+		{constraint.BusinessDay(json), "2015-07-14", false},
+		{constraint.BusinessDay(json), "2015-07-15", true},
+	} {
+		err := fixture[0].(constraint.Constraint).Validate(fixture[1])
+		e.Expect(err == nil).ToBe(fixture[2].(bool))
+	}
+}
@@ -0,0 +1,217 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CompositeError is returned by composite constraints, it holds the
+// errors of the inner constraints that failed
+type CompositeError struct {
+	name     string
+	sentinel error
+	message  string
+	errors   []error
+}
+
+// Error returns an error message, LabelPlaceholder being rendered as
+// DefaultLabel
+func (ce *CompositeError) Error() string {
+	return strings.Replace(ce.message, LabelPlaceholder, DefaultLabel, -1)
+}
+
+// Name returns the name of the composite constraint
+func (ce *CompositeError) Name() string {
+	return ce.name
+}
+
+// Unwrap returns the sentinel error of the violation, if any, and the
+// errors of the inner constraints
+func (ce *CompositeError) Unwrap() []error {
+	if ce.sentinel == nil {
+		return ce.errors
+	}
+	return append([]error{ce.sentinel}, ce.errors...)
+}
+
+// Code returns the code of the violation, the code of the first inner
+// error when the composite constraint has no code of its own
+func (ce *CompositeError) Code() Code {
+	return CodeOf(ce)
+}
+
+// Errors returns the errors of the inner constraints
+func (ce *CompositeError) Errors() []error {
+	return ce.errors
+}
+
+// AtLeastOneOf returns a constraint satisfied when at least one of
+// constraints is satisfied
+func AtLeastOneOf(constraints ...Constraint) Constraint {
+	return &atLeastOneOf{constraints}
+}
+
+type atLeastOneOf struct {
+	constraints []Constraint
+}
+
+// ValidatesNil returns true if one of the constraints validates nil
+func (c *atLeastOneOf) ValidatesNil() bool {
+	return anyValidatesNil(c.constraints)
+}
+
+// Validate returns an error if the constraint is violated
+func (c *atLeastOneOf) Validate(value interface{}) error {
+	return c.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext returns an error if the constraint is violated
+func (c *atLeastOneOf) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	errs := []error{}
+	messages := []string{AtLeastOneOfMessage}
+	for i, constraint := range c.constraints {
+		err := Execute(ctx, constraint, value)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+		messages = append(messages, fmt.Sprintf("[%d] %s", i+1, messageOf(err)))
+	}
+	return &CompositeError{name: "AtLeastOneOf", sentinel: ErrNoneSatisfied, message: strings.Join(messages, " "), errors: errs}
+}
+
+// Sequentially returns a constraint validating constraints one after the
+// other and stopping at the first violation
+func Sequentially(constraints ...Constraint) Constraint {
+	return &sequentially{constraints}
+}
+
+type sequentially struct {
+	constraints []Constraint
+}
+
+// ValidatesNil returns true if one of the constraints validates nil
+func (c *sequentially) ValidatesNil() bool {
+	return anyValidatesNil(c.constraints)
+}
+
+// Validate returns an error if the constraint is violated
+func (c *sequentially) Validate(value interface{}) error {
+	return c.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext returns the error of the first violated constraint
+func (c *sequentially) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	for _, constraint := range c.constraints {
+		if err := Execute(ctx, constraint, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Not returns a constraint satisfied when constraint is violated
+func Not(constraint Constraint) Constraint {
+	return &not{constraint}
+}
+
+type not struct {
+	constraint Constraint
+}
+
+// ValidatesNil returns true if the inner constraint validates nil
+func (c *not) ValidatesNil() bool {
+	return validatesNil(c.constraint)
+}
+
+// Validate returns an error if the constraint is violated
+func (c *not) Validate(value interface{}) error {
+	return c.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext returns an error if the inner constraint is satisfied.
+// Errors of the inner constraint that aren't violations, such as type
+// mismatches or lookup failures, are returned as is
+func (c *not) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	err := Execute(ctx, c.constraint, value)
+	switch {
+	case err == nil:
+		return NewError(ErrSatisfied, NotMessage)
+	case IsSystemError(err) || isTypeMismatch(err):
+		return err
+	}
+	return nil
+}
+
+// isTypeMismatch returns true if err reports a value of a type that the
+// constraint cannot validate rather than a violation
+func isTypeMismatch(err error) bool {
+	return errors.Is(err, ErrNotString) || errors.Is(err, ErrNotNumber) || errors.Is(err, ErrNotTime)
+}
+
+// NewCompound returns a compound constraint, a reusable named bundle
+// of constraints which are all validated. For instance
+//
+//	func StrongPassword() Constraint {
+//		return NewCompound("StrongPassword", NotBlank(), Length(12, 128), Regexp(digit))
+//	}
+func NewCompound(name string, constraints ...Constraint) *Compound {
+	return &Compound{name, constraints}
+}
+
+// Compound represents a named bundle of constraints
+type Compound struct {
+	name        string
+	constraints []Constraint
+}
+
+// Name returns the name of the compound
+func (c Compound) Name() string {
+	return c.name
+}
+
+// Constraints returns the constraints of the compound
+func (c Compound) Constraints() []Constraint {
+	return c.constraints
+}
+
+// ValidatesNil returns true if one of the constraints validates nil
+func (c Compound) ValidatesNil() bool {
+	return anyValidatesNil(c.constraints)
+}
+
+// Validate returns an error if the constraint is violated
+func (c *Compound) Validate(value interface{}) error {
+	return c.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext validates every constraint of the compound and returns
+// a CompositeError holding all the violations
+func (c *Compound) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	errs := []error{}
+	messages := []string{}
+	for _, constraint := range c.constraints {
+		if err := Execute(ctx, constraint, value); err != nil {
+			errs = append(errs, err)
+			messages = append(messages, messageOf(err))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &CompositeError{name: c.name, message: strings.Join(messages, ". "), errors: errs}
+}
+
+// anyValidatesNil returns true if one of constraints validates nil
+func anyValidatesNil(constraints []Constraint) bool {
+	for _, constraint := range constraints {
+		if validatesNil(constraint) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+func StrongPassword() constraint.Constraint {
+	return constraint.NewCompound("StrongPassword",
+		constraint.Length(8, 64),
+		constraint.Regexp(regexp.MustCompile("[0-9]")),
+		constraint.Regexp(regexp.MustCompile("[A-Z]")))
+}
+
+var phone = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+func TestComposite(t *testing.T) {
+	e := expect.New(t)
+	emailOrPhone := constraint.AtLeastOneOf(constraint.Email(), constraint.Regexp(phone))
+	e.Expect(emailOrPhone.Validate("john@example.com") == nil).ToBe(true)
+	e.Expect(emailOrPhone.Validate("+33612345678") == nil).ToBe(true)
+	err := emailOrPhone.Validate("john")
+	e.Expect(err.Error()).ToBe("This value should satisfy at least one of the following constraints: [1] This value is not a valid email address [2] This value is not valid")
+	e.Expect(len(err.(*constraint.CompositeError).Errors())).ToBe(2)
+
+	sequence := constraint.Sequentially(constraint.NotBlank(), constraint.Length(3, 10), constraint.Regexp(regexp.MustCompile("^[a-z]+$")))
+	e.Expect(sequence.Validate("john") == nil).ToBe(true)
+	e.Expect(sequence.Validate("").Error()).ToBe("This value should not be blank")
+	e.Expect(sequence.Validate("JOHN").Error()).ToBe("This value is not valid")
+
+	notAdmin := constraint.Not(constraint.Regexp(regexp.MustCompile("^(admin|root)$")))
+	e.Expect(notAdmin.Validate("john") == nil).ToBe(true)
+	e.Expect(notAdmin.Validate("root").Error()).ToBe("This value should not satisfy the constraint")
+	// errors that aren't violations are propagated
+	e.Expect(errors.Is(constraint.Not(constraint.Email()).Validate(42), constraint.ErrNotString)).ToBe(true)
+	e.Expect(errors.Is(constraint.Not(constraint.LessThan(10)).Validate("ten"), constraint.ErrNotNumber)).ToBe(true)
+	errDown := errors.New("connection refused")
+	down := constraint.LookupFunc(func(ctx context.Context, value interface{}) (bool, error) { return false, errDown })
+	err = constraint.Not(constraint.Exists(down)).Validate("john")
+	var lookupError *constraint.LookupError
+	e.Expect(errors.As(err, &lookupError)).ToBe(true)
+	e.Expect(errors.Is(err, errDown)).ToBe(true)
+
+	e.Expect(StrongPassword().Validate("Passw0rdPassw0rd") == nil).ToBe(true)
+	err = StrongPassword().Validate("pass")
+	e.Expect(err.(*constraint.CompositeError).Name()).ToBe("StrongPassword")
+	e.Expect(len(err.(*constraint.CompositeError).Errors())).ToBe(3)
+}
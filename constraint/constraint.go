@@ -5,12 +5,15 @@
 package constraint
 
 import (
+	"database/sql/driver"
 	"errors"
 	"fmt"
-	"log"
-	"net/url"
+	"math"
 	"reflect"
 	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // Constraint represents a constraint that can be validated
@@ -18,30 +21,190 @@ type Constraint interface {
 	Validate(interface{}) error
 }
 
-// NewFieldConstraint returns a constraint for a field of an struct
+// NewFieldConstraint returns a constraint for a field of an struct, fieldName
+// being a field name or a property path such as Address.City or Items[*].SKU
 func NewFieldConstraint(fieldName string, constraint Constraint) Constraint {
-	return &FieldConstraint{fieldName: fieldName, constraint: constraint}
+	path, err := ParsePropertyPath(fieldName)
+	return &FieldConstraint{fieldName: fieldName, constraint: constraint, path: path, pathErr: err}
 }
 
-// FieldConstraint Represents a field constraint
+// FieldConstraint Represents a field constraint. The value of the field
+// is unwrapped before being validated, see Unwrap
 type FieldConstraint struct {
 	fieldName  string
 	constraint Constraint
+	path       PropertyPath
+	pathErr    error
+	label      string
+	sensitive  bool
+	position   int
 }
 
 // FieldError is a field error implementing the Error interface
 type FieldError struct {
 	error
-	fieldName  string
-	typeString string
+	fieldName    string
+	typeString   string
+	label        string
+	invalidValue interface{}
+	sensitive    bool
+	path         PropertyPath
+	position     int
+	message      string
+	defaultLabel string
+}
+
+// NewFieldError returns the violation err of the field fieldName of a
+// struct of type typeString. It reports violations found outside of field
+// constraints, such as conversion errors when binding forms, which are
+// ordered before the violations of field constraints
+func NewFieldError(fieldName string, typeString string, err error) FieldError {
+	path, _ := ParsePropertyPath(fieldName)
+	return FieldError{error: err, fieldName: fieldName, typeString: typeString, path: path, position: -1}
+}
+
+// WithLabel returns a copy of the violation with the label of the field
+func (fe FieldError) WithLabel(label string) FieldError {
+	fe.label = label
+	return fe
+}
+
+// WithInvalidValue returns a copy of the violation with its invalid value,
+// already masked if the field is sensitive
+func (fe FieldError) WithInvalidValue(invalidValue interface{}, sensitive bool) FieldError {
+	fe.invalidValue = invalidValue
+	fe.sensitive = sensitive
+	return fe
+}
+
+// Translate returns a copy of the violation whose message is translated in
+// locale by translator, as well as its label, DefaultLabel when the field
+// has none. The violation is returned as is if its message has no translation
+func (fe FieldError) Translate(translator Translator, locale string) FieldError {
+	message := translate(translator, locale, fe.error)
+	if message == "" {
+		return fe
+	}
+	fe.message = message
+	if fe.label == "" {
+		fe.defaultLabel, _ = translator.Translate(locale, DefaultLabel)
+	} else if label, ok := translator.Translate(locale, fe.label); ok {
+		fe.label = label
+	}
+	return fe
 }
 
-// Error returns an error message
+// Error returns an error message, LabelPlaceholder being replaced by the
+// label of the field or DefaultLabel and ValuePlaceholder by the invalid
+// value. Translated messages are returned instead of the one of the error
+// when the field constraint was validated with a translator
 func (fe FieldError) Error() string {
-	return fe.error.Error()
+	message := fe.message
+	if message == "" {
+		message = messageOf(fe.error)
+	}
+	label := fe.label
+	if label == "" {
+		label = fe.defaultLabel
+	}
+	if label == "" {
+		label = DefaultLabel
+	}
+	message = strings.Replace(message, LabelPlaceholder, label, -1)
+	return strings.Replace(message, ValuePlaceholder, fmt.Sprint(fe.invalidValue), -1)
+}
+
+// Code returns the code of the violation, ErrInvalid when the constraint
+// returned an error without code such as the ones of errors.New
+func (fe FieldError) Code() Code {
+	if code := CodeOf(fe.error); code != "" {
+		return code
+	}
+	return ErrInvalid
+}
+
+// Severity returns the severity of the violation
+func (fe FieldError) Severity() Severity {
+	return SeverityOf(fe.error)
+}
+
+// Payload returns the payload of the violation, if any
+func (fe FieldError) Payload() map[string]interface{} {
+	return PayloadOf(fe.error)
+}
+
+// Params returns the arguments of the message template of the violation,
+// such as the limit of a length constraint
+func (fe FieldError) Params() []interface{} {
+	var violation *Error
+	if errors.As(fe.error, &violation) {
+		return violation.args
+	}
+	return nil
+}
+
+// InvalidValue returns the value that violated the constraint, masked by
+// the redactor of the execution context if the field is sensitive
+func (fe FieldError) InvalidValue() interface{} {
+	return fe.invalidValue
+}
+
+// Sensitive returns true if the field is sensitive
+func (fe FieldError) Sensitive() bool {
+	return fe.sensitive
+}
+
+// PropertyPath returns the concrete property path of the field, wildcards
+// being replaced by indexes or keys
+func (fe FieldError) PropertyPath() PropertyPath {
+	return fe.path
+}
+
+// Position returns the position of the field constraint in the metadata
+// of the struct, violations being ordered by declaration
+func (fe FieldError) Position() int {
+	return fe.position
+}
+
+// Compare orders violations by the position of their field constraints,
+// then by property path and message. It returns -1, 0 or +1
+func (fe FieldError) Compare(other FieldError) int {
+	switch {
+	case fe.position < other.position:
+		return -1
+	case fe.position > other.position:
+		return 1
+	}
+	if c := fe.path.Compare(other.path); c != 0 {
+		return c
+	}
+	return strings.Compare(fe.Error(), other.Error())
 }
 
-// FieldName returns the name the field in the struct validated
+// Equal returns true if fe and other are violations of the same field with
+// the same code, message, severity, invalid value and payload
+func (fe FieldError) Equal(other FieldError) bool {
+	return fe.fieldName == other.fieldName &&
+		fe.typeString == other.typeString &&
+		fe.Code() == other.Code() &&
+		fe.Error() == other.Error() &&
+		fe.Severity() == other.Severity() &&
+		reflect.DeepEqual(fe.invalidValue, other.invalidValue) &&
+		reflect.DeepEqual(fe.Payload(), other.Payload())
+}
+
+// Label returns the label of the field
+func (fe FieldError) Label() string {
+	return fe.label
+}
+
+// Unwrap returns the error returned by the field constraint
+func (fe FieldError) Unwrap() error {
+	return fe.error
+}
+
+// FieldName returns the property path of the field in the struct validated,
+// wildcards being replaced by indexes or keys
 func (fe FieldError) FieldName() string {
 	return fe.fieldName
 }
@@ -51,62 +214,275 @@ func (fe FieldError) Type() string {
 	return fe.typeString
 }
 
+// Errors is a list of errors returned by constraints validating several
+// values, such as field constraints with wildcards
+type Errors []error
+
+// Error returns the error messages
+func (e Errors) Error() string {
+	messages := []string{}
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors
+func (e Errors) Unwrap() []error {
+	return e
+}
+
 // Validate validates a field constraint
 func (fc *FieldConstraint) Validate(value interface{}) error {
+	return fc.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext validates a field constraint within an execution context,
+// it returns Errors when several values reached by a wildcard are invalid
+func (fc *FieldConstraint) ValidateContext(ctx *ExecutionContext, value interface{}) error {
 	v := reflect.ValueOf(value)
-	if v.Kind() == reflect.Ptr {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
 		v = v.Elem()
 	}
 	if reflect.Struct != v.Kind() {
-		log.Panicf("%v is not a struct", fmt.Sprint(value))
+		return fmt.Errorf("constraint: %s is not a struct", fmt.Sprint(value))
+	}
+	if fc.pathErr != nil {
+		return fmt.Errorf("constraint: %s", fc.pathErr)
+	}
+	values, err := fc.path.Resolve(v.Interface())
+	if err != nil {
+		return fmt.Errorf("constraint: %s: %s", v.Type(), err)
+	}
+	errs := Errors{}
+	for _, propertyValue := range values {
+		if err := Execute(ctx, fc.constraint, propertyValue.Value); err != nil {
+			fieldName := propertyValue.Path.Format(ctx.TagName())
+			invalidValue := Unwrap(propertyValue.Value)
+			if fc.sensitive {
+				invalidValue = ctx.Redactor().Redact(fieldName, invalidValue)
+			}
+			errs = append(errs, FieldError{
+				error:        err,
+				fieldName:    fieldName,
+				typeString:   v.Type().String(),
+				label:        fc.label,
+				invalidValue: invalidValue,
+				sensitive:    fc.sensitive,
+				path:         propertyValue.Path,
+				position:     fc.position,
+			}.Translate(ctx.Translator(), ctx.Locale()))
+		}
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return errs
+}
+
+// translate returns the message of err translated in locale, or an empty
+// string if there is no translation. Errors wrapping several errors, such
+// as the ones of composite constraints, aren't translated
+func translate(translator Translator, locale string, err error) string {
+	if translator == nil || locale == "" {
+		return ""
+	}
+	violation, ok := err.(*Error)
+	for !ok {
+		wrapper, isWrapper := err.(interface{ Unwrap() error })
+		if !isWrapper {
+			return ""
+		}
+		err = wrapper.Unwrap()
+		violation, ok = err.(*Error)
+	}
+	template, ok := translator.Translate(locale, violation.template)
+	if !ok {
+		return ""
+	}
+	return NewError(violation.sentinel, template, violation.args...).format()
+}
+
+// FieldName returns the name, or property path, of the field
+func (fc FieldConstraint) FieldName() string {
+	return fc.fieldName
+}
+
+// Label returns the label of the field
+func (fc FieldConstraint) Label() string {
+	return fc.label
+}
+
+// SetLabel sets the label of the field, used to render error messages
+// such as "Email address should not be blank"
+func (fc *FieldConstraint) SetLabel(label string) *FieldConstraint {
+	fc.label = label
+	return fc
+}
+
+// Sensitive returns true if the field is sensitive
+func (fc FieldConstraint) Sensitive() bool {
+	return fc.sensitive
+}
+
+// SetSensitive marks the field as sensitive, its invalid values being
+// masked in violations and error messages
+func (fc *FieldConstraint) SetSensitive(sensitive bool) *FieldConstraint {
+	fc.sensitive = sensitive
+	return fc
+}
+
+// Position returns the position of the field constraint in the metadata
+// of the struct
+func (fc FieldConstraint) Position() int {
+	return fc.position
+}
+
+// SetPosition sets the position of the field constraint in the metadata
+// of the struct, used to order violations by declaration
+func (fc *FieldConstraint) SetPosition(position int) *FieldConstraint {
+	fc.position = position
+	return fc
+}
+
+// PropertyPath returns the parsed property path of the field
+func (fc FieldConstraint) PropertyPath() PropertyPath {
+	return fc.path
+}
+
+// Constraint returns the constraint validating the field
+func (fc FieldConstraint) Constraint() Constraint {
+	return fc.constraint
+}
+
+// CheckStruct checks that t is a struct, or a pointer to a struct, having an
+// exported field that can be validated by the constraint
+func (fc FieldConstraint) CheckStruct(t reflect.Type) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
 	}
-	err := fc.constraint.Validate(v.FieldByName(fc.fieldName).Interface())
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("%s is not a struct", t)
+	}
+	if fc.pathErr != nil {
+		return fc.pathErr
+	}
+	fieldType, err := fc.path.Type(t)
 	if err != nil {
-		return FieldError{error: err, fieldName: fc.fieldName, typeString: v.Type().String()}
+		return err
 	}
-	return err
+	if fieldType == nil {
+		return nil
+	}
+	if err := CheckType(fc.constraint, UnwrappedType(fieldType)); err != nil {
+		return fmt.Errorf("field %s: %T %s", fc.fieldName, fc.constraint, err)
+	}
+	return nil
 }
 
-// NotBlank returns a notBlank constraint
-func NotBlank() Constraint {
-	c := new(notBlank)
+// NotBlank returns a notBlank constraint, see IsBlank
+func NotBlank() *NotBlankConstraint {
+	c := &NotBlankConstraint{message: NotBlankMessage}
 	return c
 }
 
-type notBlank struct {
+// Required returns a constraint violated by blank values of any kind,
+// it is a NotBlank constraint with its own message
+func Required() *NotBlankConstraint {
+	return NotBlank().SetMessage(RequiredMessage)
 }
 
-func (nb *notBlank) Validate(value interface{}) error {
-	v := reflect.ValueOf(value)
-	switch v.Kind() {
-	case reflect.String:
-		if len(v.String()) <= 0 {
-			return errors.New(NotBlankMessage)
-		}
-	default:
-		return errors.New(CannotValidateNonStringMessage)
+// NotBlankConstraint represents a not blank constraint
+type NotBlankConstraint struct {
+	allowNull   bool
+	zeroIsBlank bool
+	message     string
+}
+
+// AllowNull returns true if nil values are valid
+func (nb NotBlankConstraint) AllowNull() bool {
+	return nb.allowNull
+}
+
+// SetAllowNull sets whether nil values, nil pointers and invalid sql.Null*
+// values are valid
+func (nb *NotBlankConstraint) SetAllowNull(allowNull bool) *NotBlankConstraint {
+	nb.allowNull = allowNull
+	return nb
+}
+
+// ZeroIsBlank returns true if zero numbers are blank
+func (nb NotBlankConstraint) ZeroIsBlank() bool {
+	return nb.zeroIsBlank
+}
+
+// SetZeroIsBlank sets whether zero numbers are blank
+func (nb *NotBlankConstraint) SetZeroIsBlank(zeroIsBlank bool) *NotBlankConstraint {
+	nb.zeroIsBlank = zeroIsBlank
+	return nb
+}
+
+// Message returns the error message
+func (nb NotBlankConstraint) Message() string {
+	return nb.message
+}
+
+// SetMessage sets the error message
+func (nb *NotBlankConstraint) SetMessage(message string) *NotBlankConstraint {
+	nb.message = message
+	return nb
+}
+
+// ValidatesNil returns true
+func (nb NotBlankConstraint) ValidatesNil() bool {
+	return true
+}
+
+// Validate returns an error if the constraint is violated
+func (nb *NotBlankConstraint) Validate(value interface{}) error {
+	if nb.allowNull && IsNull(value) {
+		return nil
+	}
+	if IsBlank(value, nb.zeroIsBlank) {
+		return NewError(ErrBlank, nb.message)
 	}
 	return nil
 }
 
-// Blank returns a blank constraint
-func Blank() Constraint {
-	c := new(blank)
+// Blank returns a blank constraint, see IsBlank
+func Blank() *BlankConstraint {
+	c := new(BlankConstraint)
 	return c
 }
 
-type blank struct {
+// BlankConstraint represents a blank constraint
+type BlankConstraint struct {
+	zeroIsBlank bool
 }
 
-func (nb *blank) Validate(value interface{}) error {
-	v := reflect.ValueOf(value)
-	switch v.Kind() {
-	case reflect.String:
-		if len(v.String()) > 0 {
-			return errors.New(BlankMessage)
-		}
-	default:
-		return errors.New(CannotValidateNonStringMessage)
+// ZeroIsBlank returns true if zero numbers are blank
+func (b BlankConstraint) ZeroIsBlank() bool {
+	return b.zeroIsBlank
+}
+
+// SetZeroIsBlank sets whether zero numbers are blank
+func (b *BlankConstraint) SetZeroIsBlank(zeroIsBlank bool) *BlankConstraint {
+	b.zeroIsBlank = zeroIsBlank
+	return b
+}
+
+// ValidatesNil returns true
+func (b BlankConstraint) ValidatesNil() bool {
+	return true
+}
+
+// Validate returns an error if the constraint is violated
+func (b *BlankConstraint) Validate(value interface{}) error {
+	if !IsBlank(value, b.zeroIsBlank) {
+		return NewError(ErrNotBlank, BlankMessage)
 	}
 	return nil
 }
@@ -120,9 +496,14 @@ func NotNil() Constraint {
 type notNil struct {
 }
 
+// ValidatesNil returns true
+func (c *notNil) ValidatesNil() bool {
+	return true
+}
+
 func (c *notNil) Validate(value interface{}) error {
 	if value == nil {
-		return errors.New(NotNillMessage)
+		return NewError(ErrNil, NotNillMessage)
 	}
 	return nil
 }
@@ -136,9 +517,14 @@ func Nil() Constraint {
 type nill struct {
 }
 
+// ValidatesNil returns true
+func (c *nill) ValidatesNil() bool {
+	return true
+}
+
 func (c *nill) Validate(value interface{}) error {
 	if value != nil {
-		return errors.New(NillMessage)
+		return NewError(ErrNotNil, NillMessage)
 	}
 	return nil
 }
@@ -154,7 +540,7 @@ type isTrue struct {
 
 func (c *isTrue) Validate(value interface{}) error {
 	if value != true {
-		return errors.New(TrueMessage)
+		return NewError(ErrNotTrue, TrueMessage)
 	}
 	return nil
 }
@@ -170,7 +556,7 @@ type isFalse struct {
 
 func (c *isFalse) Validate(value interface{}) error {
 	if value != false {
-		return errors.New(FalseMessage)
+		return NewError(ErrNotFalse, FalseMessage)
 	}
 	return nil
 }
@@ -189,104 +575,143 @@ type isType struct {
 // Validate returns an error if the constraint is violated
 func (c *isType) Validate(value interface{}) error {
 	if !c.theType.AssignableTo(reflect.TypeOf(value)) {
-		return fmt.Errorf(TypeMessage, c.theType.String())
+		return NewError(ErrInvalidType, TypeMessage, c.theType.String())
 	}
 	return nil
 }
 
-// Email returns an email constraint
-func Email() Constraint {
-	c := new(email)
+// CountUnit is the unit in which a length is counted
+type CountUnit int
+
+const (
+	// CountRunes counts unicode code points
+	CountRunes CountUnit = iota
+	// CountBytes counts bytes
+	CountBytes
+	// CountGraphemes counts user perceived characters (grapheme clusters)
+	CountGraphemes
+)
+
+// Charset is the charset strings are expected to be encoded in
+type Charset string
+
+const (
+	// CharsetUTF8 rejects strings that aren't valid UTF-8
+	CharsetUTF8 Charset = "UTF-8"
+	// CharsetAny disables the charset check
+	CharsetAny Charset = ""
+)
+
+// Length returns an length constraint counting runes, strings that
+// aren't valid UTF-8 are rejected
+func Length(min int, max int) *LengthConstraint {
+	c := &LengthConstraint{min: min, max: max, unit: CountRunes, charset: CharsetUTF8}
 	return c
 }
 
-type email struct {
+// LengthConstraint represents a length constraint
+type LengthConstraint struct {
+	min        int
+	max        int
+	unit       CountUnit
+	trim       bool
+	charset    Charset
+	normalizer func(string) string
 }
 
-// Validate returns an error if the constraint is violated
-func (c email) Validate(value interface{}) error {
-	var ok bool
-	var val string
-	if val, ok = value.(string); ok != true {
-		return errors.New(CannotValidateNonStringMessage)
-	}
-	if !EmailRegexp.MatchString(val) {
-		return errors.New(EmailMessage)
-	}
-	return nil
+// Unit returns the unit in which the length is counted
+func (c LengthConstraint) Unit() CountUnit {
+	return c.unit
 }
 
-// Length returns an length constraint
-func Length(min int, max int) Constraint {
-	c := &length{min, max}
+// SetUnit sets the unit in which the length is counted
+func (c *LengthConstraint) SetUnit(unit CountUnit) *LengthConstraint {
+	c.unit = unit
 	return c
 }
 
-type length struct {
-	min int
-	max int
+// Trim returns true if whitespaces are trimmed before counting
+func (c LengthConstraint) Trim() bool {
+	return c.trim
 }
 
-// Validate returns an error if the constraint is violated
-func (c length) Validate(value interface{}) error {
-	var ok bool
-	var val string
-	if val, ok = value.(string); ok != true {
-		return errors.New(CannotValidateNonStringMessage)
-	}
-	if c.min == c.max {
-		if c.min != len(val) {
-			return fmt.Errorf(ExactLengthMessage, c.min)
-		}
-	} else {
-		if !(c.min <= len(val)) {
-			return fmt.Errorf(MinMessage, c.min)
-		}
-		if !(len(val) <= c.max) {
-			return fmt.Errorf(MaxMessage, c.max)
-		}
-	}
-	return nil
+// SetTrim sets whether leading and trailing whitespaces are trimmed
+// before counting
+func (c *LengthConstraint) SetTrim(trim bool) *LengthConstraint {
+	c.trim = trim
+	return c
+}
+
+// Charset returns the charset values must be encoded in
+func (c LengthConstraint) Charset() Charset {
+	return c.charset
 }
 
-// URL returns an url constraint
-func URL() *URLConstraint {
-	c := new(URLConstraint)
-	c.protocols = []string{}
+// SetCharset sets the charset values must be encoded in, CharsetUTF8 by
+// default, CharsetAny disabling the check. Other charsets aren't supported,
+// they are reported when the metadata is loaded and by Validate
+func (c *LengthConstraint) SetCharset(charset Charset) *LengthConstraint {
+	c.charset = charset
 	return c
 }
 
-// URLConstraint represents an url constraint
-type URLConstraint struct {
-	protocols []string
+// SetNormalizer sets a function applied to values before counting,
+// for instance a unicode normalization
+func (c *LengthConstraint) SetNormalizer(normalizer func(string) string) *LengthConstraint {
+	c.normalizer = normalizer
+	return c
 }
 
-// Protocols return the protocols supported by the constraint
-func (c URLConstraint) Protocols() []string {
-	return c.protocols
+// checkCharset returns an error if the charset of the constraint isn't supported
+func (c LengthConstraint) checkCharset() error {
+	if c.charset != CharsetUTF8 && c.charset != CharsetAny {
+		return fmt.Errorf("constraint: unsupported charset %s", c.charset)
+	}
+	return nil
 }
 
-// SetProtocols sets the protocols supported by the constraint
-func (c *URLConstraint) SetProtocols(protocols []string) *URLConstraint {
-	c.protocols = protocols
-	return c
+// count returns the length of val in the unit of the constraint
+func (c LengthConstraint) count(val string) int {
+	switch c.unit {
+	case CountBytes:
+		return len(val)
+	case CountGraphemes:
+		return graphemeCount(val)
+	default:
+		return utf8.RuneCountInString(val)
+	}
 }
 
 // Validate returns an error if the constraint is violated
-func (c URLConstraint) Validate(value interface{}) error {
+func (c LengthConstraint) Validate(value interface{}) error {
 	var ok bool
 	var val string
 	if val, ok = value.(string); ok != true {
-		return errors.New(CannotValidateNonStringMessage)
+		return NewError(ErrNotString, CannotValidateNonStringMessage)
 	}
-	if parsedURL, err := url.Parse(val); err != nil {
-		return errors.New(URLMessage)
-	} else if c.protocols != nil && len(c.protocols) > 0 {
-		for _, protocol := range c.protocols {
-			if parsedURL.Scheme == protocol {
-				return nil
-			}
-			return errors.New(URLMessage)
+	if err := c.checkCharset(); err != nil {
+		return err
+	}
+	if c.charset == CharsetUTF8 && !utf8.ValidString(val) {
+		return NewError(ErrInvalidCharset, CharsetMessage, c.charset)
+	}
+	if c.trim {
+		val = strings.TrimSpace(val)
+	}
+	if c.normalizer != nil {
+		val = c.normalizer(val)
+	}
+	length := c.count(val)
+	if c.min == c.max {
+		if c.min != length {
+			return NewError(ErrNotEqualLength, ExactLengthMessage, c.min)
+		}
+	} else {
+		if !(c.min <= length) {
+			return NewError(ErrTooShort, MinMessage, c.min)
+		}
+		if !(length <= c.max) {
+			return NewError(ErrTooLong, MaxMessage, c.max)
 		}
 	}
 	return nil
@@ -315,43 +740,50 @@ func (c *RegexpConstraint) Validate(value interface{}) error {
 	var ok bool
 	var val string
 	if val, ok = value.(string); ok != true {
-		return errors.New(CannotValidateNonStringMessage)
+		return NewError(ErrNotString, CannotValidateNonStringMessage)
 	}
 	if c.match && !c.pattern.MatchString(val) {
-		return errors.New(RegexpMatchMessage)
+		return NewError(ErrNoMatch, RegexpMatchMessage)
 	}
 	if !c.match && c.pattern.MatchString(val) {
-		return errors.New(RegexpMatchMessage)
+		return NewError(ErrNoMatch, RegexpMatchMessage)
 	}
 	return nil
 }
 
-func Range(min, max float64) Constraint {
+// Range returns a range constraint, min and max being numbers of any kind,
+// or times given as time.Time or relative times such as "-18 years"
+func Range(min, max interface{}) Constraint {
 	return &rangeConstraint{min, max}
 }
 
 type rangeConstraint struct {
-	min float64
-	max float64
+	min interface{}
+	max interface{}
 }
 
 // Validate returns an error if the constraint is violated
 func (rc *rangeConstraint) Validate(value interface{}) error {
-	valFloat64, err := ToFloat64(value)
-	if err != nil {
-		return errors.New(ErrorNotNumberMessage)
-	}
+	return rc.ValidateContext(NewExecutionContext(), value)
+}
 
-	if !(rc.min <= valFloat64) {
-		return fmt.Errorf(RangeMinMessage, fmt.Sprint(rc.min))
+// ValidateContext returns an error if the constraint is violated
+func (rc *rangeConstraint) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	if r, err := compare(ctx, value, rc.min); err != nil {
+		return err
+	} else if r < 0 {
+		return NewError(ErrTooLow, RangeMinMessage, formatBound(rc.min))
 	}
-	if !(valFloat64 <= rc.max) {
-		return fmt.Errorf(RangeMaxMessage, fmt.Sprint(rc.max))
-
+	if r, err := compare(ctx, value, rc.max); err != nil {
+		return err
+	} else if r > 0 {
+		return NewError(ErrTooHigh, RangeMaxMessage, formatBound(rc.max))
 	}
 	return nil
 }
 
+// EqualTo returns an equal to constraint, numbers of any kind being
+// compared by value
 func EqualTo(val interface{}) Constraint {
 	return &equalTo{val}
 }
@@ -362,12 +794,14 @@ type equalTo struct {
 
 // Validate returns an error if the constraint is violated
 func (c *equalTo) Validate(value interface{}) error {
-	if c.value != value {
-		return fmt.Errorf(EqualToMessage, c.value)
+	if !equal(c.value, value) {
+		return NewError(ErrNotEqual, EqualToMessage, c.value)
 	}
 	return nil
 }
 
+// NotEqualTo returns a not equal to constraint, numbers of any kind being
+// compared by value
 func NotEqualTo(val interface{}) Constraint {
 	return &notEqualTo{val}
 }
@@ -378,73 +812,91 @@ type notEqualTo struct {
 
 // Validate returns an error if the constraint is violated
 func (c *notEqualTo) Validate(value interface{}) error {
-	if c.value == value {
-		return fmt.Errorf(NotEqualToMessage, c.value)
+	if equal(c.value, value) {
+		return NewError(ErrEqual, NotEqualToMessage, c.value)
 	}
 	return nil
 }
 
-func LessThan(value float64) Constraint {
+// LessThan returns a less than constraint
+func LessThan(value interface{}) Constraint {
 	return &lessThan{value}
 }
 
 type lessThan struct {
-	value float64
+	value interface{}
 }
 
 // Validate returns an error if the constraint is violated
 func (c lessThan) Validate(value interface{}) error {
-	if val, err := ToFloat64(value); err != nil {
-		errors.New(ErrorNotNumberMessage)
-	} else if val >= c.value {
-		return fmt.Errorf(LessThanMessage, fmt.Sprint(c.value))
+	return c.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext returns an error if the constraint is violated
+func (c lessThan) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	if r, err := compare(ctx, value, c.value); err != nil {
+		return err
+	} else if r >= 0 {
+		return NewError(ErrTooHigh, LessThanMessage, formatBound(c.value))
 	}
 	return nil
 }
 
-func LessThanOrEqual(value float64) Constraint {
+// LessThanOrEqual returns a less than or equal constraint
+func LessThanOrEqual(value interface{}) Constraint {
 	return &lessThanOrEqual{value}
 }
 
 type lessThanOrEqual struct {
-	value float64
+	value interface{}
 }
 
 // Validate returns an error if the constraint is violated
 func (c *lessThanOrEqual) Validate(value interface{}) error {
-	if val, err := ToFloat64(value); err != nil {
-		errors.New(ErrorNotNumberMessage)
-	} else if val > c.value {
-		return fmt.Errorf(LessThanOrEqualMessage, fmt.Sprint(c.value))
+	return c.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext returns an error if the constraint is violated
+func (c *lessThanOrEqual) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	if r, err := compare(ctx, value, c.value); err != nil {
+		return err
+	} else if r > 0 {
+		return NewError(ErrTooHigh, LessThanOrEqualMessage, formatBound(c.value))
 	}
 	return nil
 }
 
-func GreaterThan(value float64) Constraint {
+// GreaterThan returns a greater than constraint
+func GreaterThan(value interface{}) Constraint {
 	return &greaterThan{value}
 }
 
 type greaterThan struct {
-	value float64
+	value interface{}
 }
 
 // Validate returns an error if the constraint is violated
 func (c *greaterThan) Validate(value interface{}) error {
-	if val, err := ToFloat64(value); err != nil {
-		errors.New(ErrorNotNumberMessage)
-	} else if val <= c.value {
-		return fmt.Errorf(GreaterThanMessage, fmt.Sprint(c.value))
+	return c.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext returns an error if the constraint is violated
+func (c *greaterThan) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	if r, err := compare(ctx, value, c.value); err != nil {
+		return err
+	} else if r <= 0 {
+		return NewError(ErrTooLow, GreaterThanMessage, formatBound(c.value))
 	}
 	return nil
 }
 
 // GreaterThanOrEqual returns a great than equal constraint
-func GreaterThanOrEqual(value float64) Constraint {
+func GreaterThanOrEqual(value interface{}) Constraint {
 	return &greaterThanOrEqual{value, GreaterThanOrEqualMessage}
 }
 
 type greaterThanOrEqual struct {
-	value   float64
+	value   interface{}
 	message string
 }
 
@@ -461,10 +913,15 @@ func (c *greaterThanOrEqual) SetMessage(message string) *greaterThanOrEqual {
 
 // Validate returns an error if the constraint is violated
 func (c greaterThanOrEqual) Validate(value interface{}) error {
-	if val, err := ToFloat64(value); err != nil {
-		errors.New(ErrorNotNumberMessage)
-	} else if val < c.value {
-		return fmt.Errorf(c.message, c.value)
+	return c.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext returns an error if the constraint is violated
+func (c greaterThanOrEqual) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	if r, err := compare(ctx, value, c.value); err != nil {
+		return err
+	} else if r < 0 {
+		return NewError(ErrTooLow, c.message, formatBound(c.value))
 	}
 	return nil
 }
@@ -589,15 +1046,15 @@ func (c choice) Validate(values interface{}) error {
 			}
 		}
 	}
-	return errors.New(ChoiceMessage)
+	return NewError(ErrNoSuchChoice, ChoiceMessage)
 }
 
 func (c choice) validateArray(values []interface{}) error {
 	if len(values) < c.min {
-		return errors.New(ChoiceMinMessage)
+		return NewError(ErrTooFewChoices, ChoiceMinMessage, fmt.Sprint(c.min))
 	}
 	if c.max > 0 && len(values) > c.max {
-		return errors.New(ChoiceMaxMessage)
+		return NewError(ErrTooManyChoices, ChoiceMaxMessage, fmt.Sprint(c.max))
 	}
 	for _, value := range values {
 		index := -1
@@ -608,7 +1065,7 @@ func (c choice) validateArray(values []interface{}) error {
 			}
 		}
 		if index < 0 {
-			return errors.New(ChoiceMultipleMessage)
+			return NewError(ErrNoSuchChoice, ChoiceMultipleMessage)
 		}
 	}
 	return nil
@@ -649,11 +1106,11 @@ func (count count) Validate(value interface{}) error {
 	if f, err := ToInterfaceArray(value); err != nil {
 		return err
 	} else if count.min == count.max && len(f) != count.min {
-		return fmt.Errorf(CountExactMessage, fmt.Sprint(count.min))
+		return NewError(ErrNotEqualCount, CountExactMessage, fmt.Sprint(count.min))
 	} else if len(f) < count.min {
-		return fmt.Errorf(CountMinMessage, fmt.Sprint(count.min))
+		return NewError(ErrTooFewElements, CountMinMessage, fmt.Sprint(count.min))
 	} else if count.max < len(f) {
-		return fmt.Errorf(CountMaxMessage, fmt.Sprint(count.max))
+		return NewError(ErrTooManyElements, CountMaxMessage, fmt.Sprint(count.max))
 	}
 	return nil
 }
@@ -681,6 +1138,59 @@ func ToInterfaceArray(value interface{}) ([]interface{}, error) {
 
 }
 
+// IsNull returns true if value is nil, a nil pointer, interface, map,
+// slice, channel or function, or a driver.Valuer such as sql.NullString
+// whose value is nil
+func IsNull(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		if v.IsNil() {
+			return true
+		}
+	}
+	if valuer, ok := value.(driver.Valuer); ok {
+		if val, err := valuer.Value(); err == nil && val == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBlank returns true if value is blank. nil values, empty strings, empty
+// slices, maps and arrays, zero time.Time and driver.Valuer such as
+// sql.NullString whose value is nil or blank are blank. Pointers and
+// interfaces are dereferenced. Zero numbers are blank when zeroIsBlank is true
+func IsBlank(value interface{}, zeroIsBlank bool) bool {
+	if IsNull(value) {
+		return true
+	}
+	switch v := value.(type) {
+	case time.Time:
+		return v.IsZero()
+	case driver.Valuer:
+		if val, err := v.Value(); err == nil {
+			return IsBlank(val, zeroIsBlank)
+		}
+		return false
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return IsBlank(v.Elem().Interface(), zeroIsBlank)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return zeroIsBlank && v.IsZero()
+	}
+	return false
+}
+
 // IsArrayorSlice returns true if value is
 // an array or a slice
 func IsArrayorSlice(value interface{}) bool {
@@ -693,59 +1203,112 @@ func IsArrayorSlice(value interface{}) bool {
 	}
 }
 
-// ToFloat64 converts a number to a float64 or returns an error
+// ToFloat64 converts a number of any kind to a float64 or returns an error,
+// large integers and exact numbers are rounded to the nearest float64
 func ToFloat64(value interface{}) (float64, error) {
-	switch v := value.(type) {
-	case int:
-		return float64(v), nil
-	case int8:
-		return float64(v), nil
-	case int32:
-		return float64(v), nil
-	case int64:
-		return float64(v), nil
-	case float32:
-		return float64(v), nil
-	case float64:
-		return v, nil
-	default:
+	n, err := toNumber(value)
+	if err != nil {
 		return 0, fmt.Errorf("Cant convert %s to float64", fmt.Sprint(value))
 	}
+	if n.inf != 0 {
+		return math.Inf(n.inf), nil
+	}
+	f, _ := n.rat.Float64()
+	return f, nil
+}
+
+// compare compares value to bound, numbers being compared exactly and times
+// to either a time.Time or a relative time resolved with the context clock.
+// It returns an error with ErrorNotNumberMessage if value isn't a number
+func compare(ctx *ExecutionContext, value interface{}, bound interface{}) (int, error) {
+	if isTimeBound(bound) {
+		return compareTimes(ctx, value, bound)
+	}
+	if !IsNumber(bound) {
+		return 0, fmt.Errorf("constraint: bound %s is not a number", fmt.Sprint(bound))
+	}
+	r, err := CompareNumbers(value, bound)
+	if err != nil {
+		return 0, NewError(ErrNotNumber, ErrorNotNumberMessage)
+	}
+	return r, nil
+}
+
+// equal returns true if a and b are equal, numbers of any kind being
+// compared by value
+func equal(a interface{}, b interface{}) bool {
+	if r, err := CompareNumbers(a, b); err == nil {
+		return r == 0
+	}
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Equal(tb)
+		}
+	}
+	if a == nil || b == nil {
+		return a == b
+	}
+	if reflect.TypeOf(a).Comparable() && reflect.TypeOf(b).Comparable() {
+		return a == b
+	}
+	return reflect.DeepEqual(a, b)
 }
 
-// validation error messages
+// validation error messages, LabelPlaceholder being rendered as the label
+// of the field or DefaultLabel. The constants are templates and are no
+// longer equal to the messages of the errors, see CodeOf to identify them
 const (
-	NotBlankMessage                = "This value should not be blank"
-	NotNillMessage                 = "This value should not be nil"
-	NillMessage                    = "This value should be nil"
-	BlankMessage                   = "This value should be blank"
+	NotBlankMessage                = "{{ label }} should not be blank"
+	NotNillMessage                 = "{{ label }} should not be nil"
+	NillMessage                    = "{{ label }} should be nil"
+	BlankMessage                   = "{{ label }} should be blank"
 	CannotValidateNonStringMessage = "Cannot validate this value (not a string)"
-	TrueMessage                    = "This value should be true"
-	FalseMessage                   = "This value should be false"
-	TypeMessage                    = "This value should be of type %s"
-	EmailMessage                   = "This value is not a valid email address"
-	MinMessage                     = "This value is too short. It should have %d characters or more."
-	MaxMessage                     = "This value is too long. It should have %d characters or less"
-	ExactLengthMessage             = "This value should have exactly %d characters"
-	URLMessage                     = "This value is not a valid URL."
-	RegexpMatchMessage             = "This value is not valid"
-	RangeMinMessage                = "This value should be %s or more"
-	RangeMaxMessage                = "This value should be %s or less"
-	ErrorNotNumberMessage          = "This value should be a valid number"
-	ErrorNotArrayMessage           = "This value should be a valid array or slice"
-	EqualToMessage                 = "This value should be equal to %v"
-	NotEqualToMessage              = "This value should not be equal to %v"
-	LessThanMessage                = "This value should be less than %s"
-	LessThanOrEqualMessage         = "This value should be less than or equal to %s"
-	GreaterThanMessage             = "This value should be greater than %s"
-	GreaterThanOrEqualMessage      = "This value should be greater than or equal to %s"
-	ChoiceMessage                  = "The value you selected is not a valid choice"
-	ChoiceMinMessage               = "You must select at least %s choices"
-	ChoiceMaxMessage               = "You must select at most %s choices"
-	ChoiceMultipleMessage          = "One or more of the given values is invalid"
-	CountMinMessage                = "This collection should contain %s elements or more"
-	CountMaxMessage                = "This collection should contain %s elements or less"
-	CountExactMessage              = "This collection should contain exactly %s elements"
+	TrueMessage                    = "{{ label }} should be true"
+	FalseMessage                   = "{{ label }} should be false"
+	TypeMessage                    = "{{ label }} should be of type %s"
+	EmailMessage                   = "{{ label }} is not a valid email address"
+	MinMessage                     = "{{ label }} is too short. It should have %d characters or more."
+	MaxMessage                     = "{{ label }} is too long. It should have %d characters or less"
+	ExactLengthMessage             = "{{ label }} should have exactly %d characters"
+	URLMessage                     = "{{ label }} is not a valid URL."
+	RegexpMatchMessage             = "{{ label }} is not valid"
+	RangeMinMessage                = "{{ label }} should be %s or more"
+	RangeMaxMessage                = "{{ label }} should be %s or less"
+	ErrorNotNumberMessage          = "{{ label }} should be a valid number"
+	ErrorNotArrayMessage           = "{{ label }} should be a valid array or slice"
+	EqualToMessage                 = "{{ label }} should be equal to %v"
+	NotEqualToMessage              = "{{ label }} should not be equal to %v"
+	LessThanMessage                = "{{ label }} should be less than %s"
+	LessThanOrEqualMessage         = "{{ label }} should be less than or equal to %s"
+	GreaterThanMessage             = "{{ label }} should be greater than %s"
+	GreaterThanOrEqualMessage      = "{{ label }} should be greater than or equal to %s"
+	ChoiceMessage                  = "{{ label }} is not a valid choice"
+	ChoiceMinMessage               = "{{ label }} should contain at least %s choices"
+	ChoiceMaxMessage               = "{{ label }} should contain at most %s choices"
+	ChoiceMultipleMessage          = "{{ label }} contains one or more invalid choices"
+	CountMinMessage                = "{{ label }} should contain %s elements or more"
+	CountMaxMessage                = "{{ label }} should contain %s elements or less"
+	CountExactMessage              = "{{ label }} should contain exactly %s elements"
+	UniqueMessage                  = "{{ label }} is already used"
+	ExistsMessage                  = "{{ label }} does not exist"
+	AtLeastOneOfMessage            = "{{ label }} should satisfy at least one of the following constraints:"
+	NotMessage                     = "{{ label }} should not satisfy the constraint"
+	ErrorNotTimeMessage            = "{{ label }} should be a valid time"
+	DateMessage                    = "{{ label }} is not a valid date"
+	TimeMessage                    = "{{ label }} is not a valid time"
+	DateTimeMessage                = "{{ label }} is not a valid datetime"
+	MinAgeMessage                  = "{{ label }} should correspond to an age of %d years or more"
+	MaxAgeMessage                  = "{{ label }} should correspond to an age of %d years or less"
+	NotInPastMessage               = "{{ label }} should not be in the past"
+	NotInFutureMessage             = "{{ label }} should not be in the future"
+	BusinessDayMessage             = "{{ label }} should be a business day"
+	DurationMessage                = "{{ label }} is not a valid duration"
+	DurationMinMessage             = "{{ label }} should be %s or more"
+	DurationMaxMessage             = "{{ label }} should be %s or less"
+	CharsetMessage                 = "{{ label }} does not match the expected %s charset"
+	RequiredMessage                = "{{ label }} is required"
+	DisposableEmailMessage         = "{{ label }} should not be a disposable email address"
+	EmailTypoMessage               = "{{ label }} may have a misspelled domain, did you mean %s?"
 )
 
 var (
@@ -4,9 +4,14 @@
 package constraint_test
 
 import (
+	"database/sql"
+	"encoding/json"
+	"math"
+	"math/big"
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/interactiv/expect"
 	"github.com/interactiv/validator/constraint"
@@ -14,6 +19,12 @@ import (
 
 type list []interface{}
 
+func TestRequiredMessage(t *testing.T) {
+	e := expect.New(t)
+	e.Expect(constraint.Required().Validate(nil).Error()).ToBe("This value is required")
+	e.Expect(constraint.NotBlank().Validate([]int{}).Error()).ToBe("This value should not be blank")
+}
+
 func TestConstraints(t *testing.T) {
 	e := expect.New(t)
 	for _, fixture := range fixtures {
@@ -23,13 +34,53 @@ func TestConstraints(t *testing.T) {
 	}
 }
 
+func TestUnsupportedCharset(t *testing.T) {
+	e := expect.New(t)
+	latin1 := constraint.Length(1, 4).SetCharset("ISO-8859-1")
+	err := latin1.Validate("caf\xe9")
+	e.Expect(err == nil).ToBe(false)
+	e.Expect(constraint.CodeOf(err)).ToBe(constraint.Code(""))
+	e.Expect(constraint.CheckType(latin1, reflect.TypeOf("")) == nil).ToBe(false)
+	e.Expect(constraint.CheckType(constraint.Length(1, 4), reflect.TypeOf("")) == nil).ToBe(true)
+}
+
 type Example struct{}
 
+type Celsius float32
+
+var empty, example = "", "example"
+
 var fixtures = []list{
 	list{constraint.Blank(), "", true},
 	list{constraint.Blank(), "example", false},
 	list{constraint.NotBlank(), "example", true},
 	list{constraint.NotBlank(), "", false},
+	list{constraint.NotBlank(), nil, false},
+	list{constraint.NotBlank().SetAllowNull(true), nil, true},
+	list{constraint.NotBlank().SetAllowNull(true), (*string)(nil), true},
+	list{constraint.NotBlank().SetAllowNull(true), "", false},
+	list{constraint.NotBlank(), 0, true},
+	list{constraint.NotBlank().SetZeroIsBlank(true), 0, false},
+	list{constraint.NotBlank().SetZeroIsBlank(true), uint8(1), true},
+	list{constraint.NotBlank(), []int{}, false},
+	list{constraint.NotBlank(), []int{0}, true},
+	list{constraint.NotBlank(), map[string]int{}, false},
+	list{constraint.NotBlank(), &empty, false},
+	list{constraint.NotBlank(), &example, true},
+	list{constraint.NotBlank(), time.Time{}, false},
+	list{constraint.NotBlank(), time.Now(), true},
+	list{constraint.NotBlank(), sql.NullString{}, false},
+	list{constraint.NotBlank(), sql.NullString{Valid: true}, false},
+	list{constraint.NotBlank(), sql.NullInt64{Valid: true}, true},
+	list{constraint.NotBlank().SetAllowNull(true), sql.NullInt64{}, true},
+	list{constraint.NotBlank(), Example{}, true},
+	list{constraint.Required(), "", false},
+	list{constraint.Required(), false, true},
+	list{constraint.Blank(), nil, true},
+	list{constraint.Blank(), []string{"a"}, false},
+	list{constraint.Blank(), sql.NullString{}, true},
+	list{constraint.Blank().SetZeroIsBlank(true), 0.0, true},
+	list{constraint.Blank(), 0.0, false},
 	list{constraint.NotNil(), nil, false},
 	list{constraint.NotNil(), new(Example), true},
 	list{constraint.Nil(), nil, true},
@@ -43,6 +94,16 @@ var fixtures = []list{
 	list{constraint.Length(7, 7), "example", true},
 	list{constraint.Length(7, 7), "examples", false},
 	list{constraint.Length(4, 6), "example", false},
+	list{constraint.Length(4, 4), "café", true},
+	list{constraint.Length(4, 4).SetUnit(constraint.CountBytes), "café", false},
+	list{constraint.Length(4, 4).SetUnit(constraint.CountGraphemes), "cafe\u0301", true},
+	list{constraint.Length(1, 1).SetUnit(constraint.CountGraphemes), "👍🏽", true},
+	list{constraint.Length(2, 2).SetUnit(constraint.CountGraphemes), "🇫🇷🇨🇦", true},
+	list{constraint.Length(1, 1).SetUnit(constraint.CountGraphemes), "👨‍👩‍👧", true},
+	list{constraint.Length(1, 3), "  a  ", false},
+	list{constraint.Length(1, 3).SetTrim(true), "  a  ", true},
+	list{constraint.Length(1, 3), "a\xffb", false},
+	list{constraint.Length(1, 3).SetCharset(constraint.CharsetAny), "a\xffb", true},
 	list{constraint.URL().SetProtocols([]string{"https"}), "https://example.com", true},
 	list{constraint.URL().SetProtocols([]string{"https"}), "http://example.com", false},
 	list{constraint.URL().SetProtocols([]string{"http"}), "example.com", false},
@@ -63,6 +124,24 @@ var fixtures = []list{
 	list{constraint.LessThanOrEqual(10), 10, true},
 	list{constraint.GreaterThan(5), 10, true},
 	list{constraint.GreaterThanOrEqual(5), 5, true},
+	list{constraint.Range(10, 15), int16(12), true},
+	list{constraint.Range(10, 15), uint8(16), false},
+	list{constraint.Range(10, 15), Celsius(11), true},
+	list{constraint.Range(10, 15), "12", false},
+	list{constraint.Range(int64(math.MaxInt64-1), int64(math.MaxInt64)), int64(math.MaxInt64 - 2), false},
+	list{constraint.LessThan(uint64(math.MaxUint64)), uint64(math.MaxUint64 - 1), true},
+	list{constraint.LessThan(10), "5", false},
+	list{constraint.LessThan(10), json.Number("9.99"), true},
+	list{constraint.LessThanOrEqual(10), big.NewInt(11), false},
+	list{constraint.GreaterThan(5), "10", false},
+	list{constraint.GreaterThan(big.NewRat(1, 3)), 0.34, true},
+	list{constraint.GreaterThan(5), math.Inf(1), true},
+	list{constraint.GreaterThan(5), math.NaN(), false},
+	list{constraint.GreaterThanOrEqual(5), big.NewFloat(4.5), false},
+	list{constraint.EqualTo(10), uint32(10), true},
+	list{constraint.EqualTo(10), 10.0, true},
+	list{constraint.EqualTo([]int{1}), []int{1}, true},
+	list{constraint.NotEqualTo(10), int8(10), false},
 	list{constraint.Choice([]interface{}{"a", "b"}), []string{"a"}, true},
 	list{constraint.Choice([]interface{}{"a", "b", "c"}), []interface{}{"a", "d"}, false},
 	list{constraint.Choice([]interface{}{"a", "b"}), "a", true},
@@ -0,0 +1,151 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// CommonEmailDomains are the domains of popular email providers, used to
+// suggest corrections of misspelled domains
+var CommonEmailDomains = []string{
+	"gmail.com", "googlemail.com", "yahoo.com", "yahoo.fr", "yahoo.co.uk", "hotmail.com",
+	"hotmail.fr", "outlook.com", "live.com", "msn.com", "icloud.com", "me.com", "aol.com",
+	"protonmail.com", "proton.me", "gmx.com", "gmx.de", "mail.com", "yandex.com", "orange.fr",
+	"free.fr", "laposte.net", "web.de", "comcast.net", "verizon.net",
+}
+
+// NewDomainList returns a list of domains
+func NewDomainList(domains ...string) *DomainList {
+	list := &DomainList{domains: map[string]bool{}}
+	for _, domain := range domains {
+		list.Add(domain)
+	}
+	return list
+}
+
+// DomainList is a set of domain names, such as the domains of disposable
+// email providers
+type DomainList struct {
+	domains map[string]bool
+}
+
+// Add adds domain to the list
+func (l *DomainList) Add(domain string) *DomainList {
+	l.domains[normalizeDomain(domain)] = true
+	return l
+}
+
+// Contains returns true if domain, or one of its parent domains, is in the list
+func (l DomainList) Contains(domain string) bool {
+	domain = normalizeDomain(domain)
+	for domain != "" {
+		if l.domains[domain] {
+			return true
+		}
+		i := strings.Index(domain, ".")
+		if i < 0 {
+			break
+		}
+		domain = domain[i+1:]
+	}
+	return false
+}
+
+// Len returns the number of domains in the list
+func (l DomainList) Len() int {
+	return len(l.domains)
+}
+
+// LoadDomainListFile loads a list of domains from a file, see LoadDomainList
+func LoadDomainListFile(path string) (*DomainList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadDomainList(file)
+}
+
+// LoadDomainList loads a list of domains from a text with one domain per
+// line, blank lines and lines starting with # being ignored
+func LoadDomainList(reader io.Reader) (*DomainList, error) {
+	list := NewDomainList()
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		list.Add(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// normalizeDomain returns the lower cased ASCII form of domain
+func normalizeDomain(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if ascii, err := ToASCII(domain); err == nil {
+		return ascii
+	}
+	return domain
+}
+
+// editDistance returns the Damerau-Levenshtein distance between a and b,
+// the number of insertions, deletions, substitutions and transpositions
+// of adjacent characters turning a into b
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = d[i-1][j-1] + cost
+			if d[i-1][j]+1 < d[i][j] {
+				d[i][j] = d[i-1][j] + 1
+			}
+			if d[i][j-1]+1 < d[i][j] {
+				d[i][j] = d[i][j-1] + 1
+			}
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+// suggestDomain returns the domain of domains closest to domain, if it is
+// close enough to be a likely typo
+func suggestDomain(domain string, domains []string) (string, bool) {
+	domain = normalizeDomain(domain)
+	best, bestDistance := "", -1
+	for _, candidate := range domains {
+		candidate = normalizeDomain(candidate)
+		distance := editDistance(domain, candidate)
+		if distance == 0 {
+			return "", false
+		}
+		if distance <= 2 && distance*4 <= len(candidate) && (bestDistance < 0 || distance < bestDistance) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best, bestDistance > 0
+}
@@ -0,0 +1,60 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+func TestDomainList(t *testing.T) {
+	e := expect.New(t)
+	list, err := constraint.LoadDomainListFile("testdata/disposable.txt")
+	e.Expect(err == nil).ToBe(true)
+	e.Expect(list.Len()).ToBe(4)
+	e.Expect(list.Contains("Mailinator.com")).ToBe(true)
+	e.Expect(list.Contains("eu.mailinator.com")).ToBe(true)
+	e.Expect(list.Contains("notmailinator.com")).ToBe(false)
+	e.Expect(list.Contains("example.com")).ToBe(false)
+	list, _ = constraint.LoadDomainList(strings.NewReader("bücher.de\n"))
+	e.Expect(list.Contains("xn--bcher-kva.de")).ToBe(true)
+	_, err = constraint.LoadDomainListFile("testdata/missing.txt")
+	e.Expect(err == nil).ToBe(false)
+}
+
+func TestEmailDomains(t *testing.T) {
+	e := expect.New(t)
+	blocklist := constraint.NewDomainList("mailinator.com", "yopmail.com")
+	email := constraint.Email().SetMode(constraint.EmailHTML5).SetBlocklist(blocklist).SetSuggestions(constraint.CommonEmailDomains)
+	e.Expect(email.Validate("john@example.com") == nil).ToBe(true)
+	e.Expect(email.Validate("john@gmail.com") == nil).ToBe(true)
+	e.Expect(constraint.CodeOf(email.Validate("john@mailinator.com"))).ToBe(constraint.ErrDisposableEmail)
+	e.Expect(constraint.CodeOf(email.Validate("john@mailinator"))).ToBe(constraint.Code(""))
+	e.Expect(constraint.CodeOf(email.Validate("john"))).ToBe(constraint.ErrInvalidEmail)
+
+	err := email.Validate("john@gmial.com")
+	var violation *constraint.Error
+	e.Expect(errors.As(err, &violation)).ToBe(true)
+	e.Expect(violation.Code()).ToBe(constraint.ErrEmailTypo)
+	e.Expect(violation.Args()).ToBe([]interface{}{"gmail.com"})
+	e.Expect(err.Error()).ToBe("This value may have a misspelled domain, did you mean gmail.com?")
+	e.Expect(constraint.SeverityOf(err)).ToBe(constraint.SeverityWarning)
+	e.Expect(constraint.PayloadOf(err)["suggestion"]).ToBe("gmail.com")
+	e.Expect(constraint.PayloadOf(email.Validate("john@hotmial.com"))["suggestion"]).ToBe("hotmail.com")
+	e.Expect(constraint.PayloadOf(email.Validate("john@yaho.com"))["suggestion"]).ToBe("yahoo.com")
+	e.Expect(email.Validate("john@me.org") == nil).ToBe(true)
+	e.Expect(email.Validate("john@gmx.net") == nil).ToBe(true)
+	// legitimate domains close to common ones are only warned about
+	for _, address := range []string{"hans@yahoo.de", "jo@email.com"} {
+		err := email.Validate(address)
+		e.Expect(constraint.CodeOf(err)).ToBe(constraint.ErrEmailTypo)
+		e.Expect(constraint.SeverityOf(err)).ToBe(constraint.SeverityWarning)
+	}
+	// blocking violations keep the severity error
+	e.Expect(constraint.SeverityOf(email.Validate("john@mailinator.com"))).ToBe(constraint.SeverityError)
+}
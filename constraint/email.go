@@ -0,0 +1,206 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"net"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+// EmailMode is the way email addresses are validated
+type EmailMode int
+
+const (
+	// EmailLoose only checks that addresses look like x@y.z, see EmailRegexp
+	EmailLoose EmailMode = iota
+	// EmailHTML5 validates addresses like the email inputs of browsers,
+	// see HTML5EmailRegexp
+	EmailHTML5
+	// EmailStrict parses addresses as defined by RFC 5322 with net/mail
+	// and enforces the length limits of RFC 5321
+	EmailStrict
+)
+
+const (
+	// maxEmailLength is the maximum length of an address in a SMTP path
+	maxEmailLength = 254
+	// maxLocalPartLength is the maximum length of the local part of an address
+	maxLocalPartLength = 64
+	// maxDomainLength is the maximum length of a domain name
+	maxDomainLength = 253
+)
+
+var (
+	// HTML5EmailRegexp is the pattern of valid email addresses defined by
+	// the WHATWG HTML specification
+	HTML5EmailRegexp = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+	// html5LocalPartRegexp is the pattern of the local parts of HTML5 email
+	// addresses, non ASCII characters being allowed
+	html5LocalPartRegexp = regexp.MustCompile("^(?:[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]|[^\\x00-\\x7F])+$")
+	// domainLabelRegexp is the pattern of the labels of ASCII domain names
+	domainLabelRegexp = regexp.MustCompile("^[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$")
+)
+
+// Email returns an email constraint validating addresses in the loose
+// mode, internationalized addresses being accepted
+func Email() *EmailConstraint {
+	return &EmailConstraint{mode: EmailLoose, international: true, message: EmailMessage}
+}
+
+// EmailConstraint represents an email constraint
+type EmailConstraint struct {
+	mode          EmailMode
+	international bool
+	message       string
+	blocklist     *DomainList
+	suggestions   []string
+}
+
+// Mode returns the validation mode
+func (c EmailConstraint) Mode() EmailMode {
+	return c.mode
+}
+
+// SetMode sets the validation mode, EmailLoose by default
+func (c *EmailConstraint) SetMode(mode EmailMode) *EmailConstraint {
+	c.mode = mode
+	return c
+}
+
+// International returns true if internationalized addresses are accepted
+func (c EmailConstraint) International() bool {
+	return c.international
+}
+
+// SetInternational sets whether UTF-8 local parts and internationalized
+// domain names are accepted, domains being converted to punycode before
+// being validated in the HTML5 and strict modes
+func (c *EmailConstraint) SetInternational(international bool) *EmailConstraint {
+	c.international = international
+	return c
+}
+
+// Message returns the error message
+func (c EmailConstraint) Message() string {
+	return c.message
+}
+
+// SetMessage sets the error message
+func (c *EmailConstraint) SetMessage(message string) *EmailConstraint {
+	c.message = message
+	return c
+}
+
+// Blocklist returns the list of rejected domains
+func (c EmailConstraint) Blocklist() *DomainList {
+	return c.blocklist
+}
+
+// SetBlocklist sets a list of rejected domains, such as the domains of
+// disposable email providers loaded with LoadDomainListFile. Subdomains
+// of the domains of the list are rejected as well
+func (c *EmailConstraint) SetBlocklist(blocklist *DomainList) *EmailConstraint {
+	c.blocklist = blocklist
+	return c
+}
+
+// Suggestions returns the domains suggested to correct misspelled domains
+func (c EmailConstraint) Suggestions() []string {
+	return c.suggestions
+}
+
+// SetSuggestions sets the domains, such as CommonEmailDomains, suggested
+// when the domain of an address is a likely typo of one of them like
+// gmial.com. Since legitimate domains such as yahoo.de may be close to
+// a suggested one, the violation has the severity SeverityWarning and
+// doesn't block values when warnings are ignored. The suggested domain, not
+// the whole address which may be sensitive, is the parameter of the
+// violation and the "suggestion" of its payload
+func (c *EmailConstraint) SetSuggestions(domains []string) *EmailConstraint {
+	c.suggestions = domains
+	return c
+}
+
+// Validate returns an error if the constraint is violated
+func (c EmailConstraint) Validate(value interface{}) error {
+	val, ok := value.(string)
+	if !ok {
+		return NewError(ErrNotString, CannotValidateNonStringMessage)
+	}
+	if !c.valid(val) {
+		return NewError(ErrInvalidEmail, c.message)
+	}
+	at := strings.LastIndex(val, "@")
+	if c.blocklist != nil && c.blocklist.Contains(val[at+1:]) {
+		return NewError(ErrDisposableEmail, DisposableEmailMessage)
+	}
+	if suggestion, ok := suggestDomain(val[at+1:], c.suggestions); ok {
+		return &AnnotatedError{
+			error:    NewError(ErrEmailTypo, EmailTypoMessage, suggestion),
+			severity: SeverityWarning,
+			payload:  map[string]interface{}{"suggestion": suggestion},
+		}
+	}
+	return nil
+}
+
+// valid returns true if address is valid in the mode of the constraint
+func (c EmailConstraint) valid(address string) bool {
+	if !c.international && !isASCII(address) {
+		return false
+	}
+	if c.mode == EmailLoose {
+		return EmailRegexp.MatchString(address)
+	}
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return false
+	}
+	localPart, domain := address[:at], address[at+1:]
+	if c.mode == EmailHTML5 {
+		asciiDomain, err := ToASCII(domain)
+		return err == nil && html5LocalPartRegexp.MatchString(localPart) &&
+			HTML5EmailRegexp.MatchString("x@"+asciiDomain)
+	}
+	parsed, err := mail.ParseAddress(address)
+	if err != nil || parsed.Name != "" || !strings.HasSuffix(parsed.Address, "@"+domain) {
+		return false
+	}
+	// net/mail unquotes local parts and accepts display names or comments
+	parsedLocalPart := strings.TrimSuffix(parsed.Address, "@"+domain)
+	if localPart != parsedLocalPart && !(len(localPart) > 1 && strings.HasPrefix(localPart, "\"") && strings.HasSuffix(localPart, "\"")) {
+		return false
+	}
+	if len(localPart) > maxLocalPartLength {
+		return false
+	}
+	asciiDomain, ok := validDomain(domain)
+	return ok && len(localPart)+1+len(asciiDomain) <= maxEmailLength
+}
+
+// validDomain returns the ASCII form of domain and true if domain is a
+// valid domain name or an address literal such as [192.0.2.1]
+func validDomain(domain string) (string, bool) {
+	if strings.HasPrefix(domain, "[") && strings.HasSuffix(domain, "]") {
+		literal := domain[1 : len(domain)-1]
+		if strings.HasPrefix(literal, "IPv6:") {
+			ip := net.ParseIP(strings.TrimPrefix(literal, "IPv6:"))
+			return domain, ip != nil && ip.To4() == nil
+		}
+		ip := net.ParseIP(literal)
+		return domain, ip != nil && ip.To4() != nil
+	}
+	asciiDomain, err := ToASCII(domain)
+	if err != nil || len(asciiDomain) > maxDomainLength {
+		return "", false
+	}
+	for _, label := range strings.Split(asciiDomain, ".") {
+		if !domainLabelRegexp.MatchString(label) {
+			return "", false
+		}
+	}
+	return asciiDomain, true
+}
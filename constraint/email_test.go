@@ -0,0 +1,76 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+func TestEmailModes(t *testing.T) {
+	e := expect.New(t)
+	loose := constraint.Email()
+	html5 := constraint.Email().SetMode(constraint.EmailHTML5)
+	strict := constraint.Email().SetMode(constraint.EmailStrict)
+	for _, fixture := range []struct {
+		address              string
+		loose, html5, strict bool
+	}{
+		{"john@example.com", true, true, true},
+		{"john.doe+tag@sub.example.co.uk", true, true, true},
+		{"a b@c.d", true, false, false},
+		{"john@localhost", false, true, true},
+		{"john@", false, false, false},
+		{"john..doe@example.com", true, true, false},
+		{"john@-example.com", true, false, false},
+		{"John <john@example.com>", true, false, false},
+		{"\"john doe\"@example.com", true, false, true},
+		{"(comment)john@example.com", true, false, false},
+		{"john@[192.0.2.1]", true, false, true},
+		{"john@[IPv6:2001:db8::1]", false, false, true},
+		{"jöhn@bücher.de", true, true, true},
+		{"用户@例子.广告", true, true, true},
+		{strings.Repeat("a", 65) + "@example.com", true, true, false},
+		{"john@" + strings.Repeat("a", 63) + "." + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + "." + strings.Repeat("d", 63) + ".com", true, true, false},
+	} {
+		e.Expect(fixture.address + " loose " + boolString(loose.Validate(fixture.address) == nil)).ToBe(fixture.address + " loose " + boolString(fixture.loose))
+		e.Expect(fixture.address + " html5 " + boolString(html5.Validate(fixture.address) == nil)).ToBe(fixture.address + " html5 " + boolString(fixture.html5))
+		e.Expect(fixture.address + " strict " + boolString(strict.Validate(fixture.address) == nil)).ToBe(fixture.address + " strict " + boolString(fixture.strict))
+	}
+	ascii := constraint.Email().SetMode(constraint.EmailStrict).SetInternational(false)
+	e.Expect(ascii.Validate("jöhn@bücher.de") == nil).ToBe(false)
+	e.Expect(ascii.Validate("john@xn--bcher-kva.de") == nil).ToBe(true)
+	e.Expect(constraint.CodeOf(strict.Validate("a b@c.d"))).ToBe(constraint.ErrInvalidEmail)
+	e.Expect(strict.SetMessage("Invalid email").Validate("a").Error()).ToBe("Invalid email")
+}
+
+func TestPunycode(t *testing.T) {
+	e := expect.New(t)
+	for _, fixture := range [][2]string{
+		{"bücher.de", "xn--bcher-kva.de"},
+		{"例子.测试", "xn--fsqu00a.xn--0zwm56d"},
+		{"münchen.example", "xn--mnchen-3ya.example"},
+		{"пример.рф", "xn--e1afmkfd.xn--p1ai"},
+		{"example.com", "example.com"},
+	} {
+		ascii, err := constraint.ToASCII(fixture[0])
+		e.Expect(err == nil).ToBe(true)
+		e.Expect(ascii).ToBe(fixture[1])
+		unicode, err := constraint.ToUnicode(ascii)
+		e.Expect(err == nil).ToBe(true)
+		e.Expect(unicode).ToBe(fixture[0])
+	}
+	_, err := constraint.ToUnicode("xn--a$b.com")
+	e.Expect(err == nil).ToBe(false)
+}
+
+func boolString(b bool) string {
+	if b {
+		return "valid"
+	}
+	return "invalid"
+}
@@ -0,0 +1,157 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// LabelPlaceholder is replaced in error messages by the label of the
+	// field, or by DefaultLabel when the field has no label
+	LabelPlaceholder = "{{ label }}"
+	// DefaultLabel designates the invalid value in error messages, it is
+	// translated like message templates
+	DefaultLabel = "This value"
+)
+
+// Error is the error returned by constraints when a value violates them,
+// errors.Is matches it against the sentinel error of the violation
+type Error struct {
+	sentinel error
+	template string
+	args     []interface{}
+}
+
+// NewError returns an error for a violation of kind sentinel, its
+// message being template formatted with args by fmt.Sprintf
+func NewError(sentinel error, template string, args ...interface{}) *Error {
+	return &Error{sentinel: sentinel, template: template, args: args}
+}
+
+// Error returns an error message, LabelPlaceholder being rendered as
+// DefaultLabel
+func (e *Error) Error() string {
+	return strings.Replace(e.format(), LabelPlaceholder, DefaultLabel, -1)
+}
+
+// format returns the template formatted with the arguments of the error,
+// LabelPlaceholder being kept
+func (e *Error) format() string {
+	if len(e.args) == 0 {
+		return e.template
+	}
+	return fmt.Sprintf(e.template, e.args...)
+}
+
+// messageOf returns the message of err, LabelPlaceholder being kept so
+// that it can be rendered with the label of a field
+func messageOf(err error) string {
+	switch e := err.(type) {
+	case *Error:
+		return e.format()
+	case *CompositeError:
+		return e.message
+	case *AnnotatedError:
+		return messageOf(e.error)
+	}
+	return err.Error()
+}
+
+// Unwrap returns the sentinel error of the violation
+func (e *Error) Unwrap() error {
+	return e.sentinel
+}
+
+// Template returns the message template of the error
+func (e *Error) Template() string {
+	return e.template
+}
+
+// Args returns the arguments of the message template
+func (e *Error) Args() []interface{} {
+	return e.args
+}
+
+// Code is a stable machine readable code identifying the kind of a
+// violation independently of the locale of its message. Codes are
+// the sentinel errors of the violations, matched by errors.Is
+type Code string
+
+// Error returns the code
+func (c Code) Error() string {
+	return string(c)
+}
+
+// CodeOf returns the code of the violation err, or an empty code if err
+// isn't a violation
+func CodeOf(err error) Code {
+	var code Code
+	errors.As(err, &code)
+	return code
+}
+
+// IsSystemError returns true if err reports a failure of the system rather
+// than a violation of the value, such as a *LookupError or a delegating
+// constraint without validator, see ErrNoConstraintValidator
+func IsSystemError(err error) bool {
+	var lookupError *LookupError
+	return errors.As(err, &lookupError) || errors.Is(err, ErrNoConstraintValidator)
+}
+
+// Code returns the code of the violation
+func (e *Error) Code() Code {
+	return CodeOf(e.sentinel)
+}
+
+// codes of the violations of the constraints, see README.md
+const (
+	ErrInvalid          Code = "INVALID"
+	ErrBlank            Code = "IS_BLANK"
+	ErrNotBlank         Code = "NOT_BLANK"
+	ErrNil              Code = "IS_NIL"
+	ErrNotNil           Code = "NOT_NIL"
+	ErrNotString        Code = "NOT_A_STRING"
+	ErrNotTrue          Code = "NOT_TRUE"
+	ErrNotFalse         Code = "NOT_FALSE"
+	ErrInvalidType      Code = "INVALID_TYPE"
+	ErrInvalidEmail     Code = "INVALID_EMAIL"
+	ErrDisposableEmail  Code = "DISPOSABLE_EMAIL"
+	ErrEmailTypo        Code = "EMAIL_TYPO"
+	ErrTooShort         Code = "TOO_SHORT"
+	ErrTooLong          Code = "TOO_LONG"
+	ErrNotEqualLength   Code = "NOT_EQUAL_LENGTH"
+	ErrInvalidCharset   Code = "INVALID_CHARSET"
+	ErrInvalidURL       Code = "INVALID_URL"
+	ErrNoMatch          Code = "NO_MATCH"
+	ErrNotNumber        Code = "NOT_A_NUMBER"
+	ErrNotTime          Code = "NOT_A_TIME"
+	ErrTooLow           Code = "TOO_LOW"
+	ErrTooHigh          Code = "TOO_HIGH"
+	ErrNotEqual         Code = "NOT_EQUAL"
+	ErrEqual            Code = "IS_EQUAL"
+	ErrNoSuchChoice     Code = "NO_SUCH_CHOICE"
+	ErrTooFewChoices    Code = "TOO_FEW_CHOICES"
+	ErrTooManyChoices   Code = "TOO_MANY_CHOICES"
+	ErrTooFewElements   Code = "TOO_FEW_ELEMENTS"
+	ErrTooManyElements  Code = "TOO_MANY_ELEMENTS"
+	ErrNotEqualCount    Code = "NOT_EQUAL_COUNT"
+	ErrNotUnique        Code = "NOT_UNIQUE"
+	ErrNotFound         Code = "NOT_FOUND"
+	ErrNoneSatisfied    Code = "NONE_SATISFIED"
+	ErrSatisfied        Code = "IS_SATISFIED"
+	ErrInvalidDate      Code = "INVALID_DATE"
+	ErrInvalidTime      Code = "INVALID_TIME"
+	ErrInvalidDateTime  Code = "INVALID_DATETIME"
+	ErrTooYoung         Code = "TOO_YOUNG"
+	ErrTooOld           Code = "TOO_OLD"
+	ErrInPast           Code = "IN_PAST"
+	ErrInFuture         Code = "IN_FUTURE"
+	ErrNotBusinessDay   Code = "NOT_BUSINESS_DAY"
+	ErrInvalidDuration  Code = "INVALID_DURATION"
+	ErrDurationTooShort Code = "DURATION_TOO_SHORT"
+	ErrDurationTooLong  Code = "DURATION_TOO_LONG"
+)
@@ -0,0 +1,44 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	e := expect.New(t)
+	for _, fixture := range []list{
+		{constraint.NotBlank(), "", constraint.ErrBlank},
+		{constraint.Blank(), "a", constraint.ErrNotBlank},
+		{constraint.Email(), "a", constraint.ErrInvalidEmail},
+		{constraint.Email(), 1, constraint.ErrNotString},
+		{constraint.Length(2, 3), "a", constraint.ErrTooShort},
+		{constraint.Length(2, 3), "abcd", constraint.ErrTooLong},
+		{constraint.Range(2, 3), 1, constraint.ErrTooLow},
+		{constraint.LessThan(2), 3, constraint.ErrTooHigh},
+		{constraint.LessThan(2), "a", constraint.ErrNotNumber},
+		{constraint.Count(2, 3), []int{1}, constraint.ErrTooFewElements},
+		{constraint.Choice([]interface{}{1}), 2, constraint.ErrNoSuchChoice},
+		{constraint.Date(), "2015-13-01", constraint.ErrInvalidDate},
+		{constraint.AtLeastOneOf(constraint.Email(), constraint.Length(1, 2)), "abc", constraint.ErrNoneSatisfied},
+		{constraint.NewCompound("Name", constraint.Length(1, 2)), "abc", constraint.ErrTooLong},
+	} {
+		err := fixture[0].(constraint.Constraint).Validate(fixture[1])
+		e.Expect(errors.Is(err, fixture[2].(error))).ToBe(true)
+	}
+	e.Expect(constraint.CodeOf(constraint.NotBlank().Validate(""))).ToBe(constraint.Code("IS_BLANK"))
+	e.Expect(constraint.CodeOf(constraint.Length(1, 2).Validate("abc"))).ToBe(constraint.Code("TOO_LONG"))
+	e.Expect(constraint.CodeOf(errors.New("not a violation"))).ToBe(constraint.Code(""))
+	custom := constraint.Code("NOT_EVEN")
+	e.Expect(constraint.CodeOf(constraint.NewError(custom, "This value should be even"))).ToBe(custom)
+	err := constraint.NewError(constraint.ErrTooShort, constraint.MinMessage, 3)
+	e.Expect(err.Code()).ToBe(constraint.ErrTooShort)
+	e.Expect(err.Error()).ToBe("This value is too short. It should have 3 characters or more.")
+	e.Expect(err.Template()).ToBe(constraint.MinMessage)
+}
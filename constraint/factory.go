@@ -0,0 +1,213 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoConstraintValidator is returned when a delegating constraint is
+// validated without a ConstraintValidator able to handle it
+var ErrNoConstraintValidator = errors.New("constraint: no constraint validator available")
+
+// DelegatingConstraint is a constraint that only holds configuration, its
+// validation logic lives in the ConstraintValidator named by ValidatedBy
+type DelegatingConstraint interface {
+	Constraint
+	ValidatedBy() string
+}
+
+// Delegate can be embedded in configuration only constraints. Such constraints
+// cannot validate themselves and are validated by the Validator through
+// its ConstraintValidatorFactory
+type Delegate struct{}
+
+// Validate always returns ErrNoConstraintValidator
+func (Delegate) Validate(value interface{}) error {
+	return ErrNoConstraintValidator
+}
+
+// ConstraintValidator validates a value against a delegating constraint
+type ConstraintValidator interface {
+	Validate(value interface{}, constraint Constraint) error
+}
+
+// ConstraintValidatorFunc is a function implementing ConstraintValidator
+type ConstraintValidatorFunc func(value interface{}, constraint Constraint) error
+
+// Validate calls f(value, constraint)
+func (f ConstraintValidatorFunc) Validate(value interface{}, constraint Constraint) error {
+	return f(value, constraint)
+}
+
+// ConstraintValidatorFactory returns the ConstraintValidator of a constraint
+type ConstraintValidatorFactory interface {
+	GetInstance(constraint DelegatingConstraint) (ConstraintValidator, error)
+}
+
+// NewConstraintValidatorFactory returns a factory resolving validators by
+// the name returned by DelegatingConstraint.ValidatedBy
+func NewConstraintValidatorFactory() *ConstraintValidatorRegistry {
+	return &ConstraintValidatorRegistry{validators: map[string]ConstraintValidator{}}
+}
+
+// ConstraintValidatorRegistry is a ConstraintValidatorFactory holding
+// validators registered by name, typically at wiring time
+type ConstraintValidatorRegistry struct {
+	validators map[string]ConstraintValidator
+}
+
+// Register registers a validator under name
+func (r *ConstraintValidatorRegistry) Register(name string, validator ConstraintValidator) *ConstraintValidatorRegistry {
+	r.validators[name] = validator
+	return r
+}
+
+// GetInstance returns the validator registered for the constraint
+func (r *ConstraintValidatorRegistry) GetInstance(constraint DelegatingConstraint) (ConstraintValidator, error) {
+	if validator, ok := r.validators[constraint.ValidatedBy()]; ok {
+		return validator, nil
+	}
+	return nil, fmt.Errorf("%w named %q", ErrNoConstraintValidator, constraint.ValidatedBy())
+}
+
+// ExecutionContext carries the services available to constraints while
+// a value is being validated
+type ExecutionContext struct {
+	context    context.Context
+	factory    ConstraintValidatorFactory
+	clock      Clock
+	tagName    string
+	redactor   Redactor
+	translator Translator
+	locale     string
+}
+
+// NewExecutionContext returns an execution context
+func NewExecutionContext() *ExecutionContext {
+	return &ExecutionContext{context: context.Background(), factory: NewConstraintValidatorFactory(), clock: SystemClock, redactor: DefaultRedactor}
+}
+
+// Clock returns the clock used to resolve relative times
+func (ctx ExecutionContext) Clock() Clock {
+	if ctx.clock == nil {
+		return SystemClock
+	}
+	return ctx.clock
+}
+
+// SetClock sets the clock used to resolve relative times
+func (ctx *ExecutionContext) SetClock(clock Clock) *ExecutionContext {
+	ctx.clock = clock
+	return ctx
+}
+
+// Context returns the context of the validation, used by constraints
+// querying external services
+func (ctx ExecutionContext) Context() context.Context {
+	if ctx.context == nil {
+		return context.Background()
+	}
+	return ctx.context
+}
+
+// SetContext sets the context of the validation
+func (ctx *ExecutionContext) SetContext(context context.Context) *ExecutionContext {
+	ctx.context = context
+	return ctx
+}
+
+// Factory returns the constraint validator factory
+func (ctx ExecutionContext) Factory() ConstraintValidatorFactory {
+	return ctx.factory
+}
+
+// SetFactory sets the constraint validator factory
+func (ctx *ExecutionContext) SetFactory(factory ConstraintValidatorFactory) *ExecutionContext {
+	ctx.factory = factory
+	return ctx
+}
+
+// Redactor returns the redactor masking the values of sensitive fields
+func (ctx ExecutionContext) Redactor() Redactor {
+	if ctx.redactor == nil {
+		return DefaultRedactor
+	}
+	return ctx.redactor
+}
+
+// SetRedactor sets the redactor masking the values of sensitive fields
+func (ctx *ExecutionContext) SetRedactor(redactor Redactor) *ExecutionContext {
+	ctx.redactor = redactor
+	return ctx
+}
+
+// Translator returns the translator of the messages of violations
+func (ctx ExecutionContext) Translator() Translator {
+	return ctx.translator
+}
+
+// SetTranslator sets the translator of the messages of violations
+func (ctx *ExecutionContext) SetTranslator(translator Translator) *ExecutionContext {
+	ctx.translator = translator
+	return ctx
+}
+
+// Locale returns the locale in which messages are rendered
+func (ctx ExecutionContext) Locale() string {
+	return ctx.locale
+}
+
+// SetLocale sets the locale in which messages are rendered
+func (ctx *ExecutionContext) SetLocale(locale string) *ExecutionContext {
+	ctx.locale = locale
+	return ctx
+}
+
+// TagName returns the struct tag naming fields in property paths
+func (ctx ExecutionContext) TagName() string {
+	return ctx.tagName
+}
+
+// SetTagName sets the struct tag, such as json or form, naming fields in the
+// property paths of errors, go names being used when it is empty
+func (ctx *ExecutionContext) SetTagName(tagName string) *ExecutionContext {
+	ctx.tagName = tagName
+	return ctx
+}
+
+// ContextualConstraint is a constraint that needs the execution context
+type ContextualConstraint interface {
+	Constraint
+	ValidateContext(ctx *ExecutionContext, value interface{}) error
+}
+
+// Execute validates value against constraint within ctx, resolving
+// the validator of delegating constraints through the context factory.
+// nil values only are validated by constraints implementing NilValidator
+func Execute(ctx *ExecutionContext, constraint Constraint, value interface{}) error {
+	if ctx == nil {
+		ctx = NewExecutionContext()
+	}
+	if value == nil && !validatesNil(constraint) {
+		return nil
+	}
+	switch c := constraint.(type) {
+	case ContextualConstraint:
+		return c.ValidateContext(ctx, value)
+	case DelegatingConstraint:
+		if ctx.factory == nil {
+			return ErrNoConstraintValidator
+		}
+		validator, err := ctx.factory.GetInstance(c)
+		if err != nil {
+			return err
+		}
+		return validator.Validate(value, c)
+	default:
+		return constraint.Validate(value)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+type Even struct {
+	constraint.Delegate
+}
+
+func (Even) ValidatedBy() string { return "even" }
+
+func TestExecute(t *testing.T) {
+	e := expect.New(t)
+	errOdd := errors.New("odd")
+	factory := constraint.NewConstraintValidatorFactory().
+		Register("even", constraint.ConstraintValidatorFunc(func(value interface{}, c constraint.Constraint) error {
+			if value.(int)%2 != 0 {
+				return errOdd
+			}
+			return nil
+		}))
+	ctx := constraint.NewExecutionContext().SetFactory(factory)
+	e.Expect(constraint.Execute(ctx, Even{}, 2) == nil).ToBe(true)
+	e.Expect(constraint.Execute(ctx, Even{}, 3)).ToBe(errOdd)
+	e.Expect(errors.Is(Even{}.Validate(2), constraint.ErrNoConstraintValidator)).ToBe(true)
+	e.Expect(errors.Is(constraint.Execute(nil, Even{}, 2), constraint.ErrNoConstraintValidator)).ToBe(true)
+	e.Expect(constraint.Execute(ctx, constraint.NotBlank(), "") == nil).ToBe(false)
+}
@@ -0,0 +1,120 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import "unicode"
+
+// graphemeClass is the grapheme cluster break property of a rune, see
+// https://unicode.org/reports/tr29/. Only the classes needed to count
+// clusters are distinguished
+type graphemeClass int
+
+const (
+	graphemeOther graphemeClass = iota
+	graphemeCR
+	graphemeLF
+	graphemeControl
+	graphemeExtend
+	graphemeZWJ
+	graphemeRegionalIndicator
+	graphemeSpacingMark
+	graphemeL
+	graphemeV
+	graphemeT
+	graphemeLV
+	graphemeLVT
+)
+
+// classify returns the grapheme cluster break property of r
+func classify(r rune) graphemeClass {
+	switch {
+	case r == '\r':
+		return graphemeCR
+	case r == '\n':
+		return graphemeLF
+	case r == 0x200D:
+		return graphemeZWJ
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return graphemeRegionalIndicator
+	case r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F, r == 0x200C:
+		// emoji modifiers, tags and zero width non joiner
+		return graphemeExtend
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return graphemeExtend
+	case unicode.Is(unicode.Mc, r):
+		return graphemeSpacingMark
+	case unicode.In(r, unicode.Cc, unicode.Zl, unicode.Zp), unicode.Is(unicode.Cf, r) && r != 0x200D:
+		return graphemeControl
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return graphemeL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return graphemeV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return graphemeT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return graphemeLV
+		}
+		return graphemeLVT
+	}
+	return graphemeOther
+}
+
+// isPictographic approximates the Extended_Pictographic property
+func isPictographic(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF) || (r >= 0x2600 && r <= 0x27BF) ||
+		(r >= 0x2300 && r <= 0x23FF) || (r >= 0x2B00 && r <= 0x2BFF) ||
+		r == 0x00A9 || r == 0x00AE || r == 0x203C || r == 0x2049 || r == 0x2122
+}
+
+// graphemeBreak returns true if there is a grapheme cluster boundary
+// between prev and r, riCount being the number of regional indicators
+// preceding r and pictographicZWJ true if prev is a ZWJ following
+// an emoji
+func graphemeBreak(prev, next graphemeClass, r rune, riCount int, pictographicZWJ bool) bool {
+	switch {
+	case prev == graphemeCR && next == graphemeLF:
+		return false
+	case prev == graphemeCR, prev == graphemeLF, prev == graphemeControl:
+		return true
+	case next == graphemeCR, next == graphemeLF, next == graphemeControl:
+		return true
+	case prev == graphemeL && (next == graphemeL || next == graphemeV || next == graphemeLV || next == graphemeLVT):
+		return false
+	case (prev == graphemeLV || prev == graphemeV) && (next == graphemeV || next == graphemeT):
+		return false
+	case (prev == graphemeLVT || prev == graphemeT) && next == graphemeT:
+		return false
+	case next == graphemeExtend, next == graphemeZWJ, next == graphemeSpacingMark:
+		return false
+	case pictographicZWJ && isPictographic(r):
+		return false
+	case prev == graphemeRegionalIndicator && next == graphemeRegionalIndicator:
+		return riCount%2 == 0
+	}
+	return true
+}
+
+// graphemeCount returns the number of extended grapheme clusters of s
+func graphemeCount(s string) int {
+	count := 0
+	prev := graphemeOther
+	riCount := 0
+	// inPictographic is true while the current cluster started with an emoji
+	inPictographic := false
+	for i, r := range s {
+		class := classify(r)
+		if i == 0 || graphemeBreak(prev, class, r, riCount, inPictographic && prev == graphemeZWJ) {
+			count++
+			inPictographic = isPictographic(r)
+		}
+		if class == graphemeRegionalIndicator {
+			riCount++
+		} else {
+			riCount = 0
+		}
+		prev = class
+	}
+	return count
+}
@@ -0,0 +1,29 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+func TestGraphemeCount(t *testing.T) {
+	e := expect.New(t)
+	for s, count := range map[string]int{
+		"":                               0,
+		"caf\u00e9":                      4,
+		"cafe\u0301":                     4,
+		"\r\n":                           1,
+		"\U0001F44D\U0001F3FD\U0001F44D": 2,
+		"\U0001F468\u200d\U0001F469\u200d\U0001F467":         1,
+		"\U0001F1EB\U0001F1F7\U0001F1E8\U0001F1E6\U0001F1EF": 3,
+		"\ud55c\uad6d\uc5b4":                                 3,
+		"\u1100\u1161\u11a8":                                 1,
+	} {
+		e.Expect(constraint.Length(count, count).SetUnit(constraint.CountGraphemes).Validate(s) == nil).ToBe(true)
+		e.Expect(constraint.Length(count+1, count+1).SetUnit(constraint.CountGraphemes).Validate(s) == nil).ToBe(false)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Lookup tells whether a value exists in a data store
+type Lookup interface {
+	Exists(ctx context.Context, value interface{}) (bool, error)
+}
+
+// LookupFunc is a function implementing Lookup
+type LookupFunc func(ctx context.Context, value interface{}) (bool, error)
+
+// Exists calls f(ctx, value)
+func (f LookupFunc) Exists(ctx context.Context, value interface{}) (bool, error) {
+	return f(ctx, value)
+}
+
+// SQLLookup returns a Lookup running query against db. query must have
+// a single placeholder bound to the value, and return at least one row
+// when the value exists, for instance
+//
+//	SELECT 1 FROM users WHERE email = ?
+func SQLLookup(db *sql.DB, query string) Lookup {
+	return &sqlLookup{db, query}
+}
+
+type sqlLookup struct {
+	db    *sql.DB
+	query string
+}
+
+// Exists returns true if the query returns a row
+func (l *sqlLookup) Exists(ctx context.Context, value interface{}) (bool, error) {
+	rows, err := l.db.QueryContext(ctx, l.query, value)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	exists := rows.Next()
+	return exists, rows.Err()
+}
+
+// LookupError is returned by Unique and Exists when their Lookup fails, it
+// reports a failure of the data store rather than a violation of the value
+type LookupError struct {
+	err error
+}
+
+// Error returns the error of the lookup
+func (le *LookupError) Error() string {
+	return "constraint: lookup failed: " + le.err.Error()
+}
+
+// Unwrap returns the error of the lookup
+func (le *LookupError) Unwrap() error {
+	return le.err
+}
+
+// Unique returns a constraint violated when the value is found by lookup
+func Unique(lookup Lookup) Constraint {
+	return &unique{lookup}
+}
+
+type unique struct {
+	lookup Lookup
+}
+
+// Validate returns an error if the constraint is violated
+func (c *unique) Validate(value interface{}) error {
+	return c.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext returns an error if the constraint is violated
+func (c *unique) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	exists, err := c.lookup.Exists(ctx.Context(), value)
+	if err != nil {
+		return &LookupError{err}
+	}
+	if exists {
+		return NewError(ErrNotUnique, UniqueMessage)
+	}
+	return nil
+}
+
+// Exists returns a constraint violated when the value is not found by lookup
+func Exists(lookup Lookup) Constraint {
+	return &exists{lookup}
+}
+
+type exists struct {
+	lookup Lookup
+}
+
+// Validate returns an error if the constraint is violated
+func (c *exists) Validate(value interface{}) error {
+	return c.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext returns an error if the constraint is violated
+func (c *exists) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	exists, err := c.lookup.Exists(ctx.Context(), value)
+	if err != nil {
+		return &LookupError{err}
+	}
+	if !exists {
+		return NewError(ErrNotFound, ExistsMessage)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+func TestUniqueAndExists(t *testing.T) {
+	e := expect.New(t)
+	db, err := sql.Open("memory", "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	lookup := constraint.SQLLookup(db, "SELECT 1 FROM users WHERE email = ?")
+	e.Expect(constraint.Unique(lookup).Validate("jane@example.com") == nil).ToBe(true)
+	e.Expect(constraint.Unique(lookup).Validate("john@example.com").Error()).ToBe("This value is already used")
+	e.Expect(constraint.Exists(lookup).Validate("john@example.com") == nil).ToBe(true)
+	e.Expect(constraint.Exists(lookup).Validate("jane@example.com").Error()).ToBe("This value does not exist")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = constraint.Execute(constraint.NewExecutionContext().SetContext(ctx), constraint.Unique(lookup), "jane@example.com")
+	e.Expect(errors.Is(err, context.Canceled)).ToBe(true)
+}
+
+/********************************/
+/*   IN MEMORY SQL DRIVER       */
+/********************************/
+
+// tables maps a DSN to the values stored in it
+var tables = map[string]map[interface{}]bool{
+	"users": {"john@example.com": true},
+}
+
+func init() {
+	sql.Register("memory", memoryDriver{})
+}
+
+type memoryDriver struct{}
+
+func (memoryDriver) Open(name string) (driver.Conn, error) {
+	return &memoryConn{tables[name]}, nil
+}
+
+type memoryConn struct {
+	table map[interface{}]bool
+}
+
+func (c *memoryConn) Prepare(query string) (driver.Stmt, error) { return &memoryStmt{c}, nil }
+func (c *memoryConn) Close() error                              { return nil }
+func (c *memoryConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func (c *memoryConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	values := []driver.Value{}
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+	return (&memoryStmt{c}).Query(values)
+}
+
+type memoryStmt struct {
+	conn *memoryConn
+}
+
+func (s *memoryStmt) Close() error  { return nil }
+func (s *memoryStmt) NumInput() int { return 1 }
+func (s *memoryStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s *memoryStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &memoryRows{found: s.conn.table[args[0]]}, nil
+}
+
+type memoryRows struct {
+	found bool
+	read  bool
+}
+
+func (r *memoryRows) Columns() []string { return []string{"1"} }
+func (r *memoryRows) Close() error      { return nil }
+func (r *memoryRows) Next(dest []driver.Value) error {
+	if !r.found || r.read {
+		return io.EOF
+	}
+	r.read = true
+	dest[0] = int64(1)
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+// number is an exact representation of any go number, infinities
+// excepted which are represented by the sign of inf
+type number struct {
+	inf int
+	rat *big.Rat
+}
+
+// cmp compares n and m and returns -1, 0 or +1
+func (n number) cmp(m number) int {
+	switch {
+	case n.inf != 0 || m.inf != 0:
+		if n.inf < m.inf {
+			return -1
+		}
+		if n.inf > m.inf {
+			return 1
+		}
+		return 0
+	default:
+		return n.rat.Cmp(m.rat)
+	}
+}
+
+// toNumber converts any numeric kind, named numeric types included,
+// *big.Int, *big.Float, *big.Rat and json.Number to an exact number
+func toNumber(value interface{}) (number, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v != nil {
+			return number{rat: new(big.Rat).SetInt(v)}, nil
+		}
+	case big.Int:
+		return toNumber(&v)
+	case *big.Rat:
+		if v != nil {
+			return number{rat: new(big.Rat).Set(v)}, nil
+		}
+	case big.Rat:
+		return toNumber(&v)
+	case *big.Float:
+		if v != nil {
+			if v.IsInf() {
+				return number{inf: v.Sign()}, nil
+			}
+			rat, _ := v.Rat(nil)
+			return number{rat: rat}, nil
+		}
+	case big.Float:
+		return toNumber(&v)
+	case json.Number:
+		if rat, ok := new(big.Rat).SetString(v.String()); ok {
+			return number{rat: rat}, nil
+		}
+	default:
+		rv := reflect.ValueOf(value)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return number{rat: new(big.Rat).SetInt64(rv.Int())}, nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return number{rat: new(big.Rat).SetInt(new(big.Int).SetUint64(rv.Uint()))}, nil
+		case reflect.Float32, reflect.Float64:
+			f := rv.Float()
+			if math.IsInf(f, 0) {
+				return number{inf: int(math.Copysign(1, f))}, nil
+			}
+			if rat := new(big.Rat).SetFloat64(f); rat != nil {
+				return number{rat: rat}, nil
+			}
+		}
+	}
+	return number{}, fmt.Errorf("%s is not a number", fmt.Sprint(value))
+}
+
+// IsNumber returns true if value is a number that can be compared
+func IsNumber(value interface{}) bool {
+	_, err := toNumber(value)
+	return err == nil
+}
+
+// ToRat converts a number to an exact *big.Rat or returns an error
+// for non-numbers, NaN and infinities
+func ToRat(value interface{}) (*big.Rat, error) {
+	n, err := toNumber(value)
+	if err != nil {
+		return nil, err
+	}
+	if n.inf != 0 {
+		return nil, fmt.Errorf("%s is not a finite number", fmt.Sprint(value))
+	}
+	return n.rat, nil
+}
+
+// CompareNumbers compares two numbers of any kind exactly and returns
+// -1 if a < b, 0 if a == b and +1 if a > b
+func CompareNumbers(a, b interface{}) (int, error) {
+	na, err := toNumber(a)
+	if err != nil {
+		return 0, err
+	}
+	nb, err := toNumber(b)
+	if err != nil {
+		return 0, err
+	}
+	return na.cmp(nb), nil
+}
@@ -0,0 +1,51 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+func TestCompareNumbers(t *testing.T) {
+	e := expect.New(t)
+	for _, fixture := range []list{
+		{int64(math.MaxInt64), int64(math.MaxInt64 - 1), 1},
+		{uint64(math.MaxUint64), float64(math.MaxUint64), -1},
+		{int16(-3), uint(2), -1},
+		{json.Number("1e3"), 1000, 0},
+		{new(big.Int).Lsh(big.NewInt(1), 100), math.MaxFloat32, -1},
+		{big.NewRat(1, 10), 0.1, -1},
+		{math.Inf(-1), int64(math.MinInt64), -1},
+	} {
+		r, err := constraint.CompareNumbers(fixture[0], fixture[1])
+		e.Expect(err == nil).ToBe(true)
+		e.Expect(r).ToBe(fixture[2])
+	}
+	_, err := constraint.CompareNumbers("1", 1)
+	e.Expect(err == nil).ToBe(false)
+	_, err = constraint.ToRat(math.Inf(1))
+	e.Expect(err == nil).ToBe(false)
+	f, _ := constraint.ToFloat64(uint16(7))
+	e.Expect(f).ToBe(7.0)
+}
+
+func TestComparisonErrors(t *testing.T) {
+	e := expect.New(t)
+	for _, c := range []constraint.Constraint{
+		constraint.Range(1, 2),
+		constraint.LessThan(1),
+		constraint.LessThanOrEqual(1),
+		constraint.GreaterThan(1),
+		constraint.GreaterThanOrEqual(1),
+	} {
+		e.Expect(c.Validate("one").Error()).ToBe("This value should be a valid number")
+	}
+	e.Expect(constraint.GreaterThanOrEqual(5).Validate(4).Error()).ToBe("This value should be greater than or equal to 5")
+}
@@ -0,0 +1,437 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PathElementKind is the kind of an element of a property path
+type PathElementKind int
+
+const (
+	// FieldElement is a struct field such as City in Address.City
+	FieldElement PathElementKind = iota
+	// IndexElement is a slice or array index or a map key such as [0] or [fr]
+	IndexElement
+	// WildcardElement matches every element of a slice, an array or a map
+	WildcardElement
+)
+
+// PathElement is an element of a property path
+type PathElement struct {
+	Kind PathElementKind
+	// Name is the field name of a FieldElement or the index or key of an IndexElement
+	Name string
+	// tag is the tag of the struct field of a resolved FieldElement
+	tag reflect.StructTag
+}
+
+// PropertyPath is a parsed property path such as Address.City, Items[*].SKU,
+// Items[0] or Labels["fr"]
+type PropertyPath []PathElement
+
+// ParsePropertyPath parses a property path made of field names separated
+// by dots, indexes, map keys, quoted or not, and [*] wildcards
+func ParsePropertyPath(path string) (PropertyPath, error) {
+	result := PropertyPath{}
+	invalid := func() (PropertyPath, error) {
+		return nil, fmt.Errorf("invalid property path %q", path)
+	}
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return invalid()
+			}
+			key := path[i+1 : i+end]
+			if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+				result = append(result, PathElement{Kind: IndexElement, Name: key[1 : len(key)-1]})
+			} else if key == "*" {
+				result = append(result, PathElement{Kind: WildcardElement})
+			} else if key != "" {
+				result = append(result, PathElement{Kind: IndexElement, Name: key})
+			} else {
+				return invalid()
+			}
+			i += end + 1
+			if i < len(path) && path[i] == '.' {
+				i++
+				if i == len(path) {
+					return invalid()
+				}
+			}
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			name := path[i : i+end]
+			if name == "" {
+				return invalid()
+			}
+			result = append(result, PathElement{Kind: FieldElement, Name: name})
+			i += end
+			if i < len(path) && path[i] == '.' {
+				i++
+				if i == len(path) {
+					return invalid()
+				}
+			}
+		}
+	}
+	if len(result) == 0 {
+		return invalid()
+	}
+	return result, nil
+}
+
+// String returns the property path, field names being separated by dots
+// and indexes and keys enclosed in brackets
+func (p PropertyPath) String() string {
+	var b strings.Builder
+	for i, element := range p {
+		switch element.Kind {
+		case FieldElement:
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(element.Name)
+		case IndexElement:
+			b.WriteString("[" + element.Name + "]")
+		case WildcardElement:
+			b.WriteString("[*]")
+		}
+	}
+	return b.String()
+}
+
+// Format returns the property path like String, resolved fields being
+// named after the tagName tag of the struct field (such as json or form),
+// the go name being used when the tag is missing or tagName is empty
+func (p PropertyPath) Format(tagName string) string {
+	if tagName == "" {
+		return p.String()
+	}
+	named := make(PropertyPath, len(p))
+	for i, element := range p {
+		named[i] = element
+		if element.Kind == FieldElement {
+			if name, _, _ := strings.Cut(element.tag.Get(tagName), ","); name != "" && name != "-" {
+				named[i].Name = name
+			}
+		}
+	}
+	return named.String()
+}
+
+// PropertyValue is a value reached by a property path
+type PropertyValue struct {
+	// Path is the concrete path of the value, wildcards being replaced
+	// by indexes or keys
+	Path PropertyPath
+	// Value is the unwrapped value, nil when a nil pointer or a missing
+	// index or key is met on the way
+	Value interface{}
+}
+
+// Resolve returns the values reached by the path in value, each element
+// of a slice, an array or a map matched by a wildcard being returned,
+// map keys being sorted
+func (p PropertyPath) Resolve(value interface{}) ([]PropertyValue, error) {
+	v := reflect.ValueOf(value)
+	var t reflect.Type
+	if v.IsValid() {
+		t = v.Type()
+	}
+	return p.resolve(v, t, 0, PropertyPath{})
+}
+
+// resolve resolves the path from position in v, t being the static type
+// of v used to name fields when v is nil
+func (p PropertyPath) resolve(v reflect.Value, t reflect.Type, position int, concrete PropertyPath) ([]PropertyValue, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			v = reflect.Value{}
+			break
+		}
+		v = v.Elem()
+		t = v.Type()
+	}
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if position == len(p) {
+		if !v.IsValid() {
+			return []PropertyValue{{concrete, nil}}, nil
+		}
+		return []PropertyValue{{concrete, Unwrap(v.Interface())}}, nil
+	}
+	element := p[position]
+	next := func(element PathElement) PropertyPath {
+		return append(concrete[:len(concrete):len(concrete)], element)
+	}
+	if !v.IsValid() {
+		// a nil pointer or a missing element has been met, the type is
+		// only used to name the fields
+		var elementType reflect.Type
+		switch {
+		case t == nil:
+		case element.Kind == FieldElement && t.Kind() == reflect.Struct:
+			if field, err := lookupField(t, element.Name, position == len(p)-1); err == nil {
+				element.tag = field.Tag
+				elementType = field.Type
+			}
+		case element.Kind == IndexElement && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map):
+			elementType = t.Elem()
+		}
+		if element.Kind == WildcardElement {
+			return []PropertyValue{}, nil
+		}
+		return p.resolve(v, elementType, position+1, next(element))
+	}
+	switch element.Kind {
+	case FieldElement:
+		if v.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%s is not a struct", describe(concrete, v))
+		}
+		field, err := lookupField(v.Type(), element.Name, position == len(p)-1)
+		if err != nil {
+			return nil, err
+		}
+		element.tag = field.Tag
+		fieldValue, err := v.FieldByIndexErr(field.Index)
+		if err != nil {
+			// a field promoted through a nil embedded pointer has no value
+			fieldValue = reflect.Value{}
+		}
+		return p.resolve(fieldValue, field.Type, position+1, next(element))
+	case IndexElement:
+		elementValue, err := index(v, element.Name, concrete)
+		if err != nil {
+			return nil, err
+		}
+		return p.resolve(elementValue, v.Type().Elem(), position+1, next(element))
+	default:
+		results := []PropertyValue{}
+		keys, err := wildcardKeys(v, concrete)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			elementValue, _ := index(v, key, concrete)
+			values, err := p.resolve(elementValue, v.Type().Elem(), position+1, next(PathElement{Kind: IndexElement, Name: key}))
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, values...)
+		}
+		return results, nil
+	}
+}
+
+// describe names the value reached by path for error messages
+func describe(path PropertyPath, v reflect.Value) string {
+	if len(path) == 0 {
+		return v.Type().String()
+	}
+	return path.String()
+}
+
+// lookupField returns the exported field of t named name, promoted fields
+// being resolved as go does. Exported fields of embedded unexported structs
+// are accessible
+func lookupField(t reflect.Type, name string, last bool) (reflect.StructField, error) {
+	field, ok := t.FieldByName(name)
+	if !ok {
+		return field, fmt.Errorf("unknown field %s", name)
+	}
+	if field.PkgPath != "" && (last || !field.Anonymous) {
+		return field, fmt.Errorf("field %s is unexported", name)
+	}
+	return field, nil
+}
+
+// index returns the element of a slice, an array or a map at key, or an
+// invalid value when there is no such element
+func index(v reflect.Value, key string, path PropertyPath) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(key)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid index %s of %s", key, describe(path, v))
+		}
+		if i < 0 || i >= v.Len() {
+			return reflect.Value{}, nil
+		}
+		return v.Index(i), nil
+	case reflect.Map:
+		k, err := mapKey(v.Type().Key(), key)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid key %s of %s", key, describe(path, v))
+		}
+		return v.MapIndex(k), nil
+	}
+	return reflect.Value{}, fmt.Errorf("%s is not a slice, an array or a map", describe(path, v))
+}
+
+// wildcardKeys returns the indexes of a slice or an array or the sorted
+// keys of a map
+func wildcardKeys(v reflect.Value, path PropertyPath) ([]string, error) {
+	keys := []string{}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			keys = append(keys, strconv.Itoa(i))
+		}
+	case reflect.Map:
+		mapKeys := v.MapKeys()
+		sort.Slice(mapKeys, func(i, j int) bool {
+			if r, err := CompareNumbers(mapKeys[i].Interface(), mapKeys[j].Interface()); err == nil {
+				return r < 0
+			}
+			return fmt.Sprint(mapKeys[i].Interface()) < fmt.Sprint(mapKeys[j].Interface())
+		})
+		for _, key := range mapKeys {
+			keys = append(keys, fmt.Sprint(key.Interface()))
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a slice, an array or a map", describe(path, v))
+	}
+	return keys, nil
+}
+
+// mapKey converts key to a map key of type t
+func mapKey(t reflect.Type, key string) (reflect.Value, error) {
+	k := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		k.SetString(key)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, t.Bits())
+		if err != nil {
+			return k, err
+		}
+		k.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(key, 10, t.Bits())
+		if err != nil {
+			return k, err
+		}
+		k.SetUint(u)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(key)
+		if err != nil {
+			return k, err
+		}
+		k.SetBool(b)
+	default:
+		return k, fmt.Errorf("unsupported map key type %s", t)
+	}
+	return k, nil
+}
+
+// Type returns the type of the values reached by the path in values of
+// type t, or nil if it cannot be known before validation
+func (p PropertyPath) Type(t reflect.Type) (reflect.Type, error) {
+	for position, element := range p {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Interface {
+			return nil, nil
+		}
+		switch element.Kind {
+		case FieldElement:
+			if t.Kind() != reflect.Struct {
+				return nil, fmt.Errorf("%s is not a struct", describe(p[:position], reflect.Zero(t)))
+			}
+			field, err := lookupField(t, element.Name, position == len(p)-1)
+			if err != nil {
+				return nil, err
+			}
+			t = field.Type
+		default:
+			switch t.Kind() {
+			case reflect.Slice, reflect.Array:
+				if _, err := strconv.Atoi(element.Name); element.Kind == IndexElement && err != nil {
+					return nil, fmt.Errorf("invalid index %s of %s", element.Name, describe(p[:position], reflect.Zero(t)))
+				}
+			case reflect.Map:
+				if _, err := mapKey(t.Key(), element.Name); element.Kind == IndexElement && err != nil {
+					return nil, fmt.Errorf("invalid key %s of %s", element.Name, describe(p[:position], reflect.Zero(t)))
+				}
+			default:
+				return nil, fmt.Errorf("%s is not a slice, an array or a map", describe(p[:position], reflect.Zero(t)))
+			}
+			t = t.Elem()
+		}
+	}
+	return t, nil
+}
+
+// StructFields returns the struct fields of t traversed by the path, up
+// to the first interface or invalid element
+func (p PropertyPath) StructFields(t reflect.Type) []reflect.StructField {
+	fields := []reflect.StructField{}
+	for position, element := range p {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if element.Kind == FieldElement {
+			if t.Kind() != reflect.Struct {
+				return fields
+			}
+			field, err := lookupField(t, element.Name, position == len(p)-1)
+			if err != nil {
+				return fields
+			}
+			fields = append(fields, field)
+			t = field.Type
+			continue
+		}
+		switch t.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return fields
+		}
+	}
+	return fields
+}
+
+// Compare compares p to q element by element, elements being compared as
+// numbers when both are integers, such as slice indexes. It returns -1 if
+// p comes before q, +1 if it comes after and 0 if they are equal
+func (p PropertyPath) Compare(q PropertyPath) int {
+	for i := 0; i < len(p) && i < len(q); i++ {
+		if p[i].Name == q[i].Name {
+			continue
+		}
+		a, errA := strconv.Atoi(p[i].Name)
+		b, errB := strconv.Atoi(q[i].Name)
+		if errA == nil && errB == nil {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+		if p[i].Name < q[i].Name {
+			return -1
+		}
+		return 1
+	}
+	switch {
+	case len(p) < len(q):
+		return -1
+	case len(p) > len(q):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,103 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+type Address struct {
+	City string
+}
+
+type Item struct {
+	SKU string
+}
+
+type Order struct {
+	Address  *Address
+	Items    []Item
+	Labels   map[string]string
+	Quantity map[int]int
+}
+
+func TestParsePropertyPath(t *testing.T) {
+	e := expect.New(t)
+	for _, fixture := range []list{
+		{"Name", "Name", 1},
+		{"Address.City", "Address.City", 2},
+		{"Items[*].SKU", "Items[*].SKU", 3},
+		{"Items[0]", "Items[0]", 2},
+		{`Labels["fr"]`, "Labels[fr]", 2},
+		{"Labels['fr'].Name", "Labels[fr].Name", 3},
+		{"[0][1]", "[0][1]", 2},
+	} {
+		path, err := constraint.ParsePropertyPath(fixture[0].(string))
+		e.Expect(err == nil).ToBe(true)
+		e.Expect(path.String()).ToBe(fixture[1])
+		e.Expect(len(path)).ToBe(fixture[2])
+	}
+	for _, invalid := range []string{"", "Address.", "Items[", "Items[]", "Address..City"} {
+		_, err := constraint.ParsePropertyPath(invalid)
+		e.Expect(err == nil).ToBe(false)
+	}
+}
+
+func TestResolvePropertyPath(t *testing.T) {
+	e := expect.New(t)
+	order := Order{
+		Address:  &Address{City: "Paris"},
+		Items:    []Item{{"A1"}, {"B2"}},
+		Labels:   map[string]string{"fr": "Commande", "en": "Order"},
+		Quantity: map[int]int{10: 1, 2: 3},
+	}
+	resolve := func(path string) []constraint.PropertyValue {
+		p, _ := constraint.ParsePropertyPath(path)
+		values, err := p.Resolve(order)
+		e.Expect(err == nil).ToBe(true)
+		return values
+	}
+	values := resolve("Address.City")
+	e.Expect(values[0].Value).ToBe("Paris")
+	values = resolve("Items[*].SKU")
+	e.Expect(len(values)).ToBe(2)
+	e.Expect(values[1].Path.String()).ToBe("Items[1].SKU")
+	e.Expect(values[1].Value).ToBe("B2")
+	values = resolve("Items[5].SKU")
+	e.Expect(values[0].Value == nil).ToBe(true)
+	values = resolve("Labels[*]")
+	e.Expect(values[0].Path.String()).ToBe("Labels[en]")
+	values = resolve("Quantity[*]")
+	e.Expect(values[0].Path.String()).ToBe("Quantity[2]")
+	e.Expect(resolve("Labels[fr]")[0].Value).ToBe("Commande")
+	p, _ := constraint.ParsePropertyPath("Address.City")
+	values, _ = p.Resolve(Order{})
+	e.Expect(values[0].Value == nil).ToBe(true)
+	p, _ = constraint.ParsePropertyPath("Items.SKU")
+	_, err := p.Resolve(order)
+	e.Expect(err == nil).ToBe(false)
+	fieldType, err := p.Type(reflect.TypeOf(order))
+	e.Expect(err == nil).ToBe(false)
+	p, _ = constraint.ParsePropertyPath("Items[*].SKU")
+	fieldType, _ = p.Type(reflect.TypeOf(order))
+	e.Expect(fieldType).ToBe(reflect.TypeOf(""))
+	fields := p.StructFields(reflect.TypeOf(order))
+	e.Expect(len(fields)).ToBe(2)
+	e.Expect(fields[0].Name + "." + fields[1].Name).ToBe("Items.SKU")
+}
+
+func TestComparePropertyPaths(t *testing.T) {
+	e := expect.New(t)
+	p2, _ := constraint.ParsePropertyPath("Items[2].SKU")
+	p10, _ := constraint.ParsePropertyPath("Items[10].SKU")
+	items, _ := constraint.ParsePropertyPath("Items")
+	e.Expect(p2.Compare(p10)).ToBe(-1)
+	e.Expect(p10.Compare(p2)).ToBe(1)
+	e.Expect(items.Compare(p2)).ToBe(-1)
+	e.Expect(p2.Compare(p2)).ToBe(0)
+}
@@ -0,0 +1,214 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// punycode parameters, see RFC 3492
+const (
+	punycodeBase        = 36
+	punycodeTMin        = 1
+	punycodeTMax        = 26
+	punycodeSkew        = 38
+	punycodeDamp        = 700
+	punycodeInitialBias = 72
+	punycodeInitialN    = 128
+	acePrefix           = "xn--"
+)
+
+// ToASCII converts the internationalized labels of domain to their
+// punycode form prefixed by "xn--", labels being lower cased. Full IDNA
+// mapping isn't performed, domains are expected to be normalized
+func ToASCII(domain string) (string, error) {
+	labels := strings.Split(domain, ".")
+	for i, label := range labels {
+		label = strings.ToLower(label)
+		if isASCII(label) {
+			labels[i] = label
+			continue
+		}
+		encoded, err := punycodeEncode(label)
+		if err != nil {
+			return "", err
+		}
+		labels[i] = acePrefix + encoded
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// ToUnicode converts the labels of domain prefixed by "xn--" from punycode
+func ToUnicode(domain string) (string, error) {
+	labels := strings.Split(domain, ".")
+	for i, label := range labels {
+		if !strings.HasPrefix(strings.ToLower(label), acePrefix) {
+			continue
+		}
+		decoded, err := punycodeDecode(label[len(acePrefix):])
+		if err != nil {
+			return "", err
+		}
+		labels[i] = decoded
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// isASCII returns true if s only has ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// punycodeAdapt returns the bias after a code point was encoded
+func punycodeAdapt(delta, numPoints int, first bool) int {
+	if first {
+		delta /= punycodeDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+	k := 0
+	for delta > ((punycodeBase-punycodeTMin)*punycodeTMax)/2 {
+		delta /= punycodeBase - punycodeTMin
+		k += punycodeBase
+	}
+	return k + (punycodeBase-punycodeTMin+1)*delta/(delta+punycodeSkew)
+}
+
+// punycodeThreshold returns the threshold of the digit at k
+func punycodeThreshold(k, bias int) int {
+	switch {
+	case k <= bias:
+		return punycodeTMin
+	case k >= bias+punycodeTMax:
+		return punycodeTMax
+	}
+	return k - bias
+}
+
+// punycodeEncode encodes label, see RFC 3492 section 6.3
+func punycodeEncode(label string) (string, error) {
+	runes := []rune(label)
+	output := []byte{}
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			output = append(output, byte(r))
+		}
+	}
+	b := len(output)
+	h := b
+	if b > 0 {
+		output = append(output, '-')
+	}
+	n, delta, bias := punycodeInitialN, 0, punycodeInitialBias
+	for h < len(runes) {
+		m := math.MaxInt32
+		for _, r := range runes {
+			if int(r) >= n && int(r) < m {
+				m = int(r)
+			}
+		}
+		if (m - n) > (math.MaxInt32-delta)/(h+1) {
+			return "", fmt.Errorf("punycode: overflow encoding %q", label)
+		}
+		delta += (m - n) * (h + 1)
+		n = m
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+			}
+			if int(r) != n {
+				continue
+			}
+			q := delta
+			for k := punycodeBase; ; k += punycodeBase {
+				t := punycodeThreshold(k, bias)
+				if q < t {
+					break
+				}
+				output = append(output, punycodeDigit(t+(q-t)%(punycodeBase-t)))
+				q = (q - t) / (punycodeBase - t)
+			}
+			output = append(output, punycodeDigit(q))
+			bias = punycodeAdapt(delta, h+1, h == b)
+			delta = 0
+			h++
+		}
+		delta++
+		n++
+	}
+	return string(output), nil
+}
+
+// punycodeDecode decodes label, see RFC 3492 section 6.2
+func punycodeDecode(label string) (string, error) {
+	output := []rune{}
+	if i := strings.LastIndex(label, "-"); i >= 0 {
+		for _, r := range label[:i] {
+			if r >= utf8.RuneSelf {
+				return "", fmt.Errorf("punycode: invalid label %q", label)
+			}
+			output = append(output, r)
+		}
+		label = label[i+1:]
+	}
+	n, i, bias := punycodeInitialN, 0, punycodeInitialBias
+	for len(label) > 0 {
+		oldi, w := i, 1
+		for k := punycodeBase; ; k += punycodeBase {
+			if len(label) == 0 {
+				return "", fmt.Errorf("punycode: truncated label")
+			}
+			digit, ok := punycodeValue(label[0])
+			label = label[1:]
+			if !ok || digit > (math.MaxInt32-i)/w {
+				return "", fmt.Errorf("punycode: invalid label")
+			}
+			i += digit * w
+			t := punycodeThreshold(k, bias)
+			if digit < t {
+				break
+			}
+			w *= punycodeBase - t
+		}
+		bias = punycodeAdapt(i-oldi, len(output)+1, oldi == 0)
+		n += i / (len(output) + 1)
+		i %= len(output) + 1
+		if n > utf8.MaxRune {
+			return "", fmt.Errorf("punycode: invalid label")
+		}
+		output = append(output[:i], append([]rune{rune(n)}, output[i:]...)...)
+		i++
+	}
+	return string(output), nil
+}
+
+// punycodeDigit returns the character of the digit d
+func punycodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+// punycodeValue returns the value of the digit c
+func punycodeValue(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c-'0') + 26, true
+	case c >= 'a' && c <= 'z':
+		return int(c - 'a'), true
+	case c >= 'A' && c <= 'Z':
+		return int(c - 'A'), true
+	}
+	return 0, false
+}
@@ -0,0 +1,29 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+// RedactedValue is the value of sensitive fields in violations when
+// the default redactor is used
+const RedactedValue = "********"
+
+// ValuePlaceholder is replaced by the invalid value in error messages,
+// such as "{{ value }} is not a valid email address"
+const ValuePlaceholder = "{{ value }}"
+
+// Redactor masks the invalid values of sensitive fields, such as
+// passwords or card numbers, before they are captured in violations
+type Redactor interface {
+	Redact(fieldName string, value interface{}) interface{}
+}
+
+// RedactorFunc is a function implementing Redactor
+type RedactorFunc func(fieldName string, value interface{}) interface{}
+
+// Redact calls f(fieldName, value)
+func (f RedactorFunc) Redact(fieldName string, value interface{}) interface{} {
+	return f(fieldName, value)
+}
+
+// DefaultRedactor replaces every sensitive value by RedactedValue
+var DefaultRedactor Redactor = RedactorFunc(func(string, interface{}) interface{} { return RedactedValue })
@@ -0,0 +1,177 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+// Severity is the severity of a constraint violation
+type Severity int
+
+const (
+	// SeverityError is the severity of the violations making a value invalid,
+	// constraints have it unless annotated otherwise
+	SeverityError Severity = iota
+	// SeverityWarning is the severity of violations that are accepted but
+	// should be reported, such as a weak password
+	SeverityWarning
+	// SeverityInfo is the severity of informative violations
+	SeverityInfo
+)
+
+// String returns the name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+	return "error"
+}
+
+// Annotate returns a constraint reporting the violations of constraint
+// with a severity and a payload, for instance
+//
+//	Annotate(Length(12, 128)).SetSeverity(SeverityWarning).SetPayload(map[string]interface{}{"hint": "use a passphrase"})
+func Annotate(constraint Constraint) *Annotated {
+	return &Annotated{constraint: constraint, severity: SeverityError}
+}
+
+// Warning returns a constraint reporting the violations of constraint as warnings
+func Warning(constraint Constraint) *Annotated {
+	return Annotate(constraint).SetSeverity(SeverityWarning)
+}
+
+// Info returns a constraint reporting the violations of constraint as informative
+func Info(constraint Constraint) *Annotated {
+	return Annotate(constraint).SetSeverity(SeverityInfo)
+}
+
+// Annotated is a constraint with a severity and a payload
+type Annotated struct {
+	constraint Constraint
+	severity   Severity
+	payload    map[string]interface{}
+}
+
+// Constraint returns the annotated constraint
+func (a *Annotated) Constraint() Constraint {
+	return a.constraint
+}
+
+// Severity returns the severity of the violations
+func (a *Annotated) Severity() Severity {
+	return a.severity
+}
+
+// SetSeverity sets the severity of the violations
+func (a *Annotated) SetSeverity(severity Severity) *Annotated {
+	a.severity = severity
+	return a
+}
+
+// Payload returns the payload of the violations
+func (a *Annotated) Payload() map[string]interface{} {
+	return a.payload
+}
+
+// SetPayload sets an arbitrary payload attached to the violations, such
+// as a hint or a documentation link for the client
+func (a *Annotated) SetPayload(payload map[string]interface{}) *Annotated {
+	a.payload = payload
+	return a
+}
+
+// ValidatesNil returns true if the annotated constraint validates nil
+func (a *Annotated) ValidatesNil() bool {
+	return validatesNil(a.constraint)
+}
+
+// Validate returns an error if the constraint is violated
+func (a *Annotated) Validate(value interface{}) error {
+	return a.ValidateContext(NewExecutionContext(), value)
+}
+
+// ValidateContext returns an *AnnotatedError wrapping the error of the
+// annotated constraint
+func (a *Annotated) ValidateContext(ctx *ExecutionContext, value interface{}) error {
+	err := Execute(ctx, a.constraint, value)
+	if err == nil {
+		return nil
+	}
+	return &AnnotatedError{error: err, severity: a.severity, payload: a.payload}
+}
+
+// AnnotatedError is the error of an annotated constraint
+type AnnotatedError struct {
+	error
+	severity Severity
+	payload  map[string]interface{}
+}
+
+// Unwrap returns the error of the annotated constraint
+func (ae *AnnotatedError) Unwrap() error {
+	return ae.error
+}
+
+// Severity returns the severity of the violation
+func (ae *AnnotatedError) Severity() Severity {
+	return ae.severity
+}
+
+// Payload returns the payload of the violation
+func (ae *AnnotatedError) Payload() map[string]interface{} {
+	return ae.payload
+}
+
+// SeverityOf returns the severity of err, SeverityError unless err was
+// returned by an annotated constraint. The outermost annotation gives the
+// severity, composite errors being as severe as their most severe inner
+// error so that a compound mixing warnings and errors stays blocking
+func SeverityOf(err error) Severity {
+	if annotated := annotationOf(err); annotated != nil {
+		return annotated.severity
+	}
+	return SeverityError
+}
+
+// PayloadOf returns the payload of err, nil unless err was returned by an
+// annotated constraint with a payload. The payload is the one of the
+// annotation giving the severity of err, see SeverityOf
+func PayloadOf(err error) map[string]interface{} {
+	if annotated := annotationOf(err); annotated != nil {
+		return annotated.payload
+	}
+	return nil
+}
+
+// annotationOf returns the outermost annotation of err, the one of the most
+// severe inner error of composite errors, or nil if err has no annotation
+func annotationOf(err error) *AnnotatedError {
+	switch e := err.(type) {
+	case *AnnotatedError:
+		return e
+	case *CompositeError:
+		return mostSevere(e.errors)
+	case interface{ Unwrap() []error }:
+		return mostSevere(e.Unwrap())
+	case interface{ Unwrap() error }:
+		return annotationOf(e.Unwrap())
+	}
+	return nil
+}
+
+// mostSevere returns the annotation of the most severe of errs, nil if
+// one of them has no annotation and thus the severity error
+func mostSevere(errs []error) *AnnotatedError {
+	var annotated *AnnotatedError
+	for _, err := range errs {
+		a := annotationOf(err)
+		if a == nil {
+			return nil
+		}
+		if annotated == nil || a.severity < annotated.severity {
+			annotated = a
+		}
+	}
+	return annotated
+}
@@ -0,0 +1,45 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+func TestSeverity(t *testing.T) {
+	e := expect.New(t)
+	weak := constraint.Warning(constraint.Length(12, 128))
+	e.Expect(weak.Validate("correct horse battery staple") == nil).ToBe(true)
+	err := weak.Validate("secret")
+	e.Expect(err.Error()).ToBe(constraint.Length(12, 128).Validate("secret").Error())
+	e.Expect(constraint.SeverityOf(err)).ToBe(constraint.SeverityWarning)
+	e.Expect(errors.Is(err, constraint.ErrTooShort)).ToBe(true)
+	e.Expect(constraint.CodeOf(err)).ToBe(constraint.ErrTooShort)
+	e.Expect(constraint.SeverityOf(constraint.NotBlank().Validate(""))).ToBe(constraint.SeverityError)
+	e.Expect(constraint.SeverityInfo.String()).ToBe("info")
+
+	payload := map[string]interface{}{"doc": "https://example.com/passwords"}
+	annotated := constraint.Info(constraint.NotNil()).SetPayload(payload)
+	e.Expect(annotated.ValidatesNil()).ToBe(true)
+	err = constraint.Execute(constraint.NewExecutionContext(), annotated, nil)
+	e.Expect(constraint.SeverityOf(err)).ToBe(constraint.SeverityInfo)
+	e.Expect(constraint.PayloadOf(err)["doc"]).ToBe("https://example.com/passwords")
+	e.Expect(constraint.PayloadOf(errors.New("other")) == nil).ToBe(true)
+
+	// a compound is as severe as its most severe violation
+	password := constraint.NewCompound("Password", constraint.Warning(constraint.Length(12, 128)), constraint.NotBlank())
+	e.Expect(constraint.SeverityOf(password.Validate(""))).ToBe(constraint.SeverityError)
+	e.Expect(constraint.SeverityOf(password.Validate("secret"))).ToBe(constraint.SeverityWarning)
+	mixed := constraint.NewCompound("Bio", constraint.Info(constraint.Length(10, 100)), constraint.Warning(constraint.Regexp(regexp.MustCompile("^[a-z]+$"))))
+	e.Expect(constraint.SeverityOf(mixed.Validate("A1"))).ToBe(constraint.SeverityWarning)
+	// the outermost annotation wins
+	err = constraint.Warning(constraint.NewCompound("Name", constraint.NotBlank())).SetPayload(payload).Validate("")
+	e.Expect(constraint.SeverityOf(err)).ToBe(constraint.SeverityWarning)
+	e.Expect(constraint.PayloadOf(err)["doc"]).ToBe("https://example.com/passwords")
+}
@@ -0,0 +1,165 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Clock tells the current time, constraints comparing values to relative
+// times such as "now" or "-18 years" get it from the execution context
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc is a function implementing Clock
+type ClockFunc func() time.Time
+
+// Now calls f()
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the clock returning time.Now
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FixedClock returns a clock always returning t, useful in tests
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+// ParseRelativeTime returns the time described by s relatively to now.
+// s is either an absolute time in the RFC 3339 or 2006-01-02 format,
+// one of "now", "today", "tomorrow", "yesterday" or a list of signed
+// offsets such as "+30 days" or "-18 years 6 months"
+func ParseRelativeTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	s = strings.ToLower(s)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch s {
+	case "now":
+		return now, nil
+	case "today", "midnight":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return time.Time{}, fmt.Errorf("constraint: invalid relative time %q", s)
+	}
+	t := now
+	for i := 0; i < len(fields); i += 2 {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("constraint: invalid relative time %q", s)
+		}
+		switch strings.TrimSuffix(fields[i+1], "s") {
+		case "second", "sec":
+			t = t.Add(time.Duration(n) * time.Second)
+		case "minute", "min":
+			t = t.Add(time.Duration(n) * time.Minute)
+		case "hour":
+			t = t.Add(time.Duration(n) * time.Hour)
+		case "day":
+			t = t.AddDate(0, 0, n)
+		case "week":
+			t = t.AddDate(0, 0, 7*n)
+		case "month":
+			t = t.AddDate(0, n, 0)
+		case "year":
+			t = t.AddDate(n, 0, 0)
+		default:
+			return time.Time{}, fmt.Errorf("constraint: invalid relative time %q", s)
+		}
+	}
+	return t, nil
+}
+
+// compareTimes compares value to a time bound, either a time.Time or
+// a string parsed by ParseRelativeTime against the context clock
+func compareTimes(ctx *ExecutionContext, value interface{}, bound interface{}) (int, error) {
+	var b time.Time
+	switch v := bound.(type) {
+	case time.Time:
+		b = v
+	case string:
+		t, err := ParseRelativeTime(v, ctx.Clock().Now())
+		if err != nil {
+			return 0, err
+		}
+		b = t
+	}
+	t, ok := value.(time.Time)
+	if !ok {
+		return 0, NewError(ErrNotTime, ErrorNotTimeMessage)
+	}
+	return t.Compare(b), nil
+}
+
+// isTimeBound returns true if bound is a time.Time or a relative time
+func isTimeBound(bound interface{}) bool {
+	switch bound.(type) {
+	case time.Time, string:
+		return true
+	}
+	return false
+}
+
+// formatBound formats a bound for error messages
+func formatBound(bound interface{}) string {
+	if t, ok := bound.(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(bound)
+}
+
+// Date returns a constraint validating a string date formatted as 2006-01-02
+func Date() Constraint {
+	return &dateTime{"2006-01-02", ErrInvalidDate, DateMessage}
+}
+
+// Time returns a constraint validating a string time formatted as 15:04:05
+func Time() Constraint {
+	return &dateTime{"15:04:05", ErrInvalidTime, TimeMessage}
+}
+
+// DateTime returns a constraint validating a string datetime formatted
+// according to layout, 2006-01-02 15:04:05 when layout is empty
+func DateTime(layout string) Constraint {
+	if layout == "" {
+		layout = "2006-01-02 15:04:05"
+	}
+	return &dateTime{layout, ErrInvalidDateTime, DateTimeMessage}
+}
+
+type dateTime struct {
+	layout   string
+	sentinel error
+	message  string
+}
+
+// Validate returns an error if the constraint is violated
+func (c *dateTime) Validate(value interface{}) error {
+	var ok bool
+	var val string
+	if val, ok = value.(string); ok != true {
+		return NewError(ErrNotString, CannotValidateNonStringMessage)
+	}
+	if _, err := time.Parse(c.layout, val); err != nil {
+		return NewError(c.sentinel, c.message)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+var now = time.Date(2015, time.June, 15, 10, 30, 0, 0, time.UTC)
+
+func TestParseRelativeTime(t *testing.T) {
+	e := expect.New(t)
+	for _, fixture := range []list{
+		{"now", now},
+		{"today", time.Date(2015, time.June, 15, 0, 0, 0, 0, time.UTC)},
+		{"tomorrow", time.Date(2015, time.June, 16, 0, 0, 0, 0, time.UTC)},
+		{"-18 years", time.Date(1997, time.June, 15, 10, 30, 0, 0, time.UTC)},
+		{"+30 days", time.Date(2015, time.July, 15, 10, 30, 0, 0, time.UTC)},
+		{"+1 month -2 hours", time.Date(2015, time.July, 15, 8, 30, 0, 0, time.UTC)},
+		{"2015-01-02", time.Date(2015, time.January, 2, 0, 0, 0, 0, time.UTC)},
+		{"2015-01-02T08:00:00Z", time.Date(2015, time.January, 2, 8, 0, 0, 0, time.UTC)},
+	} {
+		r, err := constraint.ParseRelativeTime(fixture[0].(string), now)
+		e.Expect(err == nil).ToBe(true)
+		e.Expect(r.Equal(fixture[1].(time.Time))).ToBe(true)
+	}
+	_, err := constraint.ParseRelativeTime("+3 fortnights", now)
+	e.Expect(err == nil).ToBe(false)
+}
+
+func TestTimeComparisons(t *testing.T) {
+	e := expect.New(t)
+	ctx := constraint.NewExecutionContext().SetClock(constraint.FixedClock(now))
+	for _, fixture := range []list{
+		{constraint.LessThan("-18 years"), time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC), true},
+		{constraint.LessThan("-18 years"), time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC), false},
+		{constraint.GreaterThan("now"), now.Add(time.Hour), true},
+		{constraint.GreaterThanOrEqual("today"), now.Add(-time.Hour), true},
+		{constraint.LessThanOrEqual(now), now, true},
+		{constraint.Range("today", "+30 days"), now.AddDate(0, 0, 31), false},
+		{constraint.Range("today", "+30 days"), now.AddDate(0, 0, 2), true},
+		{constraint.EqualTo(now), now.In(time.FixedZone("CEST", 7200)), true},
+		{constraint.GreaterThan("now"), "2016-01-01", false},
+	} {
+		err := constraint.Execute(ctx, fixture[0].(constraint.Constraint), fixture[1])
+		e.Expect(err == nil).ToBe(fixture[2].(bool))
+	}
+	e.Expect(constraint.LessThan("now").Validate(3).Error()).ToBe("This value should be a valid time")
+}
+
+func TestDateTime(t *testing.T) {
+	e := expect.New(t)
+	for _, fixture := range []list{
+		{constraint.Date(), "2015-06-15", true},
+		{constraint.Date(), "2015-02-30", false},
+		{constraint.Date(), "15/06/2015", false},
+		{constraint.Time(), "10:30:00", true},
+		{constraint.Time(), "25:00:00", false},
+		{constraint.DateTime(""), "2015-06-15 10:30:00", true},
+		{constraint.DateTime(time.RFC3339), "2015-06-15T10:30:00Z", true},
+		{constraint.DateTime(time.RFC3339), "2015-06-15 10:30:00", false},
+		{constraint.Date(), 20150615, false},
+	} {
+		err := fixture[0].(constraint.Constraint).Validate(fixture[1])
+		e.Expect(err == nil).ToBe(fixture[2].(bool))
+	}
+}
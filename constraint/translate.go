@@ -0,0 +1,53 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import "sort"
+
+// Translator translates the message templates of violations, such as
+// NotBlankMessage, into the templates of a locale
+type Translator interface {
+	Translate(locale string, template string) (string, bool)
+	Locales() []string
+}
+
+// NewCatalog returns an empty catalog of translations
+func NewCatalog() *Catalog {
+	return &Catalog{translations: map[string]map[string]string{}}
+}
+
+// Catalog is a Translator holding translations in memory
+type Catalog struct {
+	translations map[string]map[string]string
+}
+
+// Add adds the translation of template in locale, translations having the
+// same verbs and placeholders as their template. DefaultLabel and the labels
+// of fields are translated like templates, for instance
+//
+//	catalog.Add("fr", constraint.MinMessage, "{{ label }} est trop courte. Elle doit avoir au moins %d caractères.").
+//		Add("fr", constraint.DefaultLabel, "Cette valeur")
+func (c *Catalog) Add(locale string, template string, translation string) *Catalog {
+	if c.translations[locale] == nil {
+		c.translations[locale] = map[string]string{}
+	}
+	c.translations[locale][template] = translation
+	return c
+}
+
+// Translate returns the translation of template in locale
+func (c *Catalog) Translate(locale string, template string) (string, bool) {
+	translation, ok := c.translations[locale][template]
+	return translation, ok
+}
+
+// Locales returns the sorted locales of the catalog
+func (c *Catalog) Locales() []string {
+	locales := []string{}
+	for locale := range c.translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
@@ -0,0 +1,28 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+func TestCatalog(t *testing.T) {
+	e := expect.New(t)
+	catalog := constraint.NewCatalog().
+		Add("fr", constraint.MinMessage, "Cette valeur est trop courte. Elle doit avoir au moins %d caractères.").
+		Add("de", constraint.NotBlankMessage, "Dieser Wert sollte nicht leer sein")
+	e.Expect(catalog.Locales()).ToBe([]string{"de", "fr"})
+	_, ok := catalog.Translate("fr", constraint.NotBlankMessage)
+	e.Expect(ok).ToBe(false)
+
+	fieldConstraint := constraint.NewFieldConstraint("Name", constraint.Length(3, 10))
+	ctx := constraint.NewExecutionContext().SetTranslator(catalog).SetLocale("fr")
+	err := fieldConstraint.(*constraint.FieldConstraint).ValidateContext(ctx, struct{ Name string }{"Al"})
+	e.Expect(err.Error()).ToBe("Cette valeur est trop courte. Elle doit avoir au moins 3 caractères.")
+	e.Expect(err.(constraint.FieldError).Code()).ToBe(constraint.ErrTooShort)
+	e.Expect(err.(constraint.FieldError).Translate(catalog, "es").Error()).ToBe(err.Error())
+}
@@ -0,0 +1,211 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"time"
+)
+
+// TypeChecker is implemented by constraints restricting the types of the
+// values they validate, metadata is checked with it when it is loaded
+type TypeChecker interface {
+	CheckType(t reflect.Type) error
+}
+
+var (
+	valuerType    = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	unwrapperType = reflect.TypeOf((*ValueUnwrapper)(nil)).Elem()
+	timeType      = reflect.TypeOf(time.Time{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+	numberTypes   = []reflect.Type{
+		reflect.TypeOf(big.Int{}), reflect.TypeOf(big.Float{}),
+		reflect.TypeOf(big.Rat{}), reflect.TypeOf(json.Number("")),
+	}
+)
+
+// UnwrappedType returns the type of the values Unwrap returns for values
+// of type t, or nil when it cannot be known before validation
+func UnwrappedType(t reflect.Type) reflect.Type {
+	for t != nil {
+		switch {
+		case t.Kind() == reflect.Ptr:
+			t = t.Elem()
+		case t.Kind() == reflect.Struct && t.NumField() == 2 && t.Field(1).Name == "Valid" &&
+			t.Field(1).Type.Kind() == reflect.Bool && t.Implements(valuerType):
+			// sql.NullString, sql.NullInt64, sql.Null[T]...
+			t = t.Field(0).Type
+		case t.Kind() == reflect.Interface, t.Implements(unwrapperType), t.Implements(valuerType):
+			return nil
+		default:
+			return t
+		}
+	}
+	return nil
+}
+
+// CheckType checks that values of type t can be validated by constraint,
+// it returns nil when constraint doesn't implement TypeChecker
+func CheckType(constraint Constraint, t reflect.Type) error {
+	if checker, ok := constraint.(TypeChecker); ok && t != nil {
+		return checker.CheckType(t)
+	}
+	return nil
+}
+
+// checkString returns an error if t isn't a string
+func checkString(t reflect.Type) error {
+	if t.Kind() != reflect.String {
+		return fmt.Errorf("expects a string, %s given", t)
+	}
+	return nil
+}
+
+// checkNumber returns an error if t isn't a number
+func checkNumber(t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return nil
+	}
+	for _, numberType := range numberTypes {
+		if t == numberType {
+			return nil
+		}
+	}
+	return fmt.Errorf("expects a number, %s given", t)
+}
+
+// checkTime returns an error if t isn't a time.Time
+func checkTime(t reflect.Type) error {
+	if t != timeType {
+		return fmt.Errorf("expects a time.Time, %s given", t)
+	}
+	return nil
+}
+
+// checkBound returns an error if t cannot be compared to bound
+func checkBound(t reflect.Type, bound interface{}) error {
+	if isTimeBound(bound) {
+		return checkTime(t)
+	}
+	return checkNumber(t)
+}
+
+// checkAll returns the first error of the checks of constraints
+func checkAll(constraints []Constraint, t reflect.Type) error {
+	for _, constraint := range constraints {
+		if err := CheckType(constraint, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckType returns an error if t isn't a string
+func (c EmailConstraint) CheckType(t reflect.Type) error { return checkString(t) }
+
+// CheckType returns an error if t isn't a string or if the charset isn't supported
+func (c LengthConstraint) CheckType(t reflect.Type) error {
+	if err := c.checkCharset(); err != nil {
+		return err
+	}
+	return checkString(t)
+}
+
+// CheckType returns an error if t isn't a string
+func (c URLConstraint) CheckType(t reflect.Type) error { return checkString(t) }
+
+// CheckType returns an error if t isn't a string
+func (c *RegexpConstraint) CheckType(t reflect.Type) error { return checkString(t) }
+
+// CheckType returns an error if t isn't a string
+func (c *dateTime) CheckType(t reflect.Type) error { return checkString(t) }
+
+// CheckType returns an error if t cannot be compared to the bounds
+func (rc *rangeConstraint) CheckType(t reflect.Type) error {
+	if err := checkBound(t, rc.min); err != nil {
+		return err
+	}
+	return checkBound(t, rc.max)
+}
+
+// CheckType returns an error if t cannot be compared to the bound
+func (c lessThan) CheckType(t reflect.Type) error { return checkBound(t, c.value) }
+
+// CheckType returns an error if t cannot be compared to the bound
+func (c *lessThanOrEqual) CheckType(t reflect.Type) error { return checkBound(t, c.value) }
+
+// CheckType returns an error if t cannot be compared to the bound
+func (c *greaterThan) CheckType(t reflect.Type) error { return checkBound(t, c.value) }
+
+// CheckType returns an error if t cannot be compared to the bound
+func (c greaterThanOrEqual) CheckType(t reflect.Type) error { return checkBound(t, c.value) }
+
+// CheckType returns an error if t isn't an array or a slice
+func (count count) CheckType(t reflect.Type) error {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return fmt.Errorf("expects an array or a slice, %s given", t)
+	}
+	return nil
+}
+
+// CheckType returns an error if t isn't a time.Time or a string
+func (c *ageConstraint) CheckType(t reflect.Type) error {
+	if checkString(t) != nil && checkTime(t) != nil {
+		return fmt.Errorf("expects a time.Time or a string, %s given", t)
+	}
+	return nil
+}
+
+// CheckType returns an error if t isn't a time.Time or a string
+func (c *businessDay) CheckType(t reflect.Type) error {
+	return (&ageConstraint{}).CheckType(t)
+}
+
+// CheckType returns an error if t isn't a time.Time or a string
+func (c *notInPast) CheckType(t reflect.Type) error {
+	return (&ageConstraint{}).CheckType(t)
+}
+
+// CheckType returns an error if t isn't a time.Time or a string
+func (c *notInFuture) CheckType(t reflect.Type) error {
+	return (&ageConstraint{}).CheckType(t)
+}
+
+// CheckType returns an error if t isn't a time.Duration or a string
+func (c *duration) CheckType(t reflect.Type) error {
+	if t != durationType && checkString(t) != nil {
+		return fmt.Errorf("expects a time.Duration or a string, %s given", t)
+	}
+	return nil
+}
+
+// CheckType returns an error if t cannot be validated by any of the constraints
+func (c *atLeastOneOf) CheckType(t reflect.Type) error {
+	var err error
+	for _, constraint := range c.constraints {
+		if err = CheckType(constraint, t); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// CheckType returns an error if t cannot be validated by one of the constraints
+func (c *sequentially) CheckType(t reflect.Type) error { return checkAll(c.constraints, t) }
+
+// CheckType returns an error if t cannot be validated by one of the constraints
+func (c Compound) CheckType(t reflect.Type) error { return checkAll(c.constraints, t) }
+
+// CheckType returns an error if t cannot be validated by the inner constraint
+func (c *not) CheckType(t reflect.Type) error { return CheckType(c.constraint, t) }
+
+// CheckType returns an error if t cannot be validated by the annotated constraint
+func (a *Annotated) CheckType(t reflect.Type) error { return CheckType(a.constraint, t) }
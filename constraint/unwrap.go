@@ -0,0 +1,74 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"database/sql/driver"
+	"reflect"
+)
+
+// ValueUnwrapper is implemented by optional types exposing the value
+// they hold, ok being false when they hold no value
+type ValueUnwrapper interface {
+	UnwrapValue() (value interface{}, ok bool)
+}
+
+// NilValidator is implemented by constraints validating missing values.
+// Other constraints are satisfied by nil values
+type NilValidator interface {
+	ValidatesNil() bool
+}
+
+// validatesNil returns true if constraint validates nil values
+func validatesNil(constraint Constraint) bool {
+	if c, ok := constraint.(NilValidator); ok {
+		return c.ValidatesNil()
+	}
+	return false
+}
+
+// Unwrap returns the value held by value. Pointers and interfaces are
+// dereferenced, ValueUnwrapper and driver.Valuer such as sql.NullString are
+// unwrapped. Unwrap returns nil when value holds no value
+func Unwrap(value interface{}) interface{} {
+	for value != nil {
+		switch v := value.(type) {
+		case ValueUnwrapper:
+			if IsNull(value) {
+				return nil
+			}
+			unwrapped, ok := v.UnwrapValue()
+			if !ok {
+				return nil
+			}
+			value = unwrapped
+			continue
+		case driver.Valuer:
+			if IsNull(value) {
+				return nil
+			}
+			unwrapped, err := v.Value()
+			if err != nil {
+				return value
+			}
+			if unwrapped == nil {
+				return nil
+			}
+			if reflect.TypeOf(unwrapped) == reflect.TypeOf(value) {
+				return value
+			}
+			value = unwrapped
+			continue
+		}
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Ptr && rv.Kind() != reflect.Interface {
+			return value
+		}
+		if rv.IsNil() {
+			return nil
+		}
+		value = rv.Elem().Interface()
+	}
+	return nil
+}
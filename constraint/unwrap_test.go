@@ -0,0 +1,46 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator/constraint"
+)
+
+type Optional struct {
+	value string
+	set   bool
+}
+
+func (o Optional) UnwrapValue() (interface{}, bool) {
+	return o.value, o.set
+}
+
+func TestUnwrap(t *testing.T) {
+	e := expect.New(t)
+	name := "john"
+	pointer := &name
+	e.Expect(constraint.Unwrap(&name)).ToBe("john")
+	e.Expect(constraint.Unwrap(&pointer)).ToBe("john")
+	e.Expect(constraint.Unwrap((*string)(nil)) == nil).ToBe(true)
+	e.Expect(constraint.Unwrap(sql.NullString{String: "john", Valid: true})).ToBe("john")
+	e.Expect(constraint.Unwrap(sql.NullString{String: "john"}) == nil).ToBe(true)
+	e.Expect(constraint.Unwrap(&sql.NullInt64{Int64: 3, Valid: true})).ToBe(int64(3))
+	e.Expect(constraint.Unwrap((*sql.NullInt64)(nil)) == nil).ToBe(true)
+	e.Expect(constraint.Unwrap(Optional{"john", true})).ToBe("john")
+	e.Expect(constraint.Unwrap(Optional{}) == nil).ToBe(true)
+	e.Expect(constraint.Unwrap(10)).ToBe(10)
+}
+
+func TestExecuteNil(t *testing.T) {
+	e := expect.New(t)
+	e.Expect(constraint.Execute(nil, constraint.Email(), nil) == nil).ToBe(true)
+	e.Expect(constraint.Execute(nil, constraint.NotNil(), nil) == nil).ToBe(false)
+	e.Expect(constraint.Execute(nil, constraint.Required(), nil) == nil).ToBe(false)
+	e.Expect(constraint.Execute(nil, constraint.Sequentially(constraint.NotBlank(), constraint.Email()), nil) == nil).ToBe(false)
+	e.Expect(constraint.Execute(nil, constraint.Sequentially(constraint.Length(1, 2), constraint.Email()), nil) == nil).ToBe(true)
+}
@@ -0,0 +1,195 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package constraint
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// URL returns an url constraint accepting absolute URLs of any protocol
+// having a host
+func URL() *URLConstraint {
+	return &URLConstraint{protocols: []string{}, requireHost: true, message: URLMessage}
+}
+
+// URLConstraint represents an url constraint
+type URLConstraint struct {
+	protocols             []string
+	requireHost           bool
+	allowRelative         bool
+	allowProtocolRelative bool
+	requireTLD            bool
+	message               string
+}
+
+// Protocols return the protocols supported by the constraint
+func (c URLConstraint) Protocols() []string {
+	return c.protocols
+}
+
+// SetProtocols sets the protocols supported by the constraint, such as
+// http and https, all protocols being supported when it is empty
+func (c *URLConstraint) SetProtocols(protocols []string) *URLConstraint {
+	c.protocols = protocols
+	return c
+}
+
+// RequireHost returns true if URLs must have a host
+func (c URLConstraint) RequireHost() bool {
+	return c.requireHost
+}
+
+// SetRequireHost sets whether URLs must have a host, true by default. URLs
+// without host include mailto:john@example.com or urn:isbn:0451450523
+func (c *URLConstraint) SetRequireHost(requireHost bool) *URLConstraint {
+	c.requireHost = requireHost
+	return c
+}
+
+// AllowRelative returns true if relative URLs are accepted
+func (c URLConstraint) AllowRelative() bool {
+	return c.allowRelative
+}
+
+// SetAllowRelative sets whether relative URLs such as /articles?page=2 are
+// accepted, protocol relative URLs included
+func (c *URLConstraint) SetAllowRelative(allowRelative bool) *URLConstraint {
+	c.allowRelative = allowRelative
+	return c
+}
+
+// AllowProtocolRelative returns true if protocol relative URLs are accepted
+func (c URLConstraint) AllowProtocolRelative() bool {
+	return c.allowProtocolRelative
+}
+
+// SetAllowProtocolRelative sets whether protocol relative URLs such as
+// //example.com/image.png are accepted
+func (c *URLConstraint) SetAllowProtocolRelative(allowProtocolRelative bool) *URLConstraint {
+	c.allowProtocolRelative = allowProtocolRelative
+	return c
+}
+
+// RequireTLD returns true if hosts must have a top level domain
+func (c URLConstraint) RequireTLD() bool {
+	return c.requireTLD
+}
+
+// SetRequireTLD sets whether hosts must be domain names ending with a top
+// level domain, rejecting hosts such as localhost or IP addresses
+func (c *URLConstraint) SetRequireTLD(requireTLD bool) *URLConstraint {
+	c.requireTLD = requireTLD
+	return c
+}
+
+// Message returns the error message
+func (c URLConstraint) Message() string {
+	return c.message
+}
+
+// SetMessage sets the error message
+func (c *URLConstraint) SetMessage(message string) *URLConstraint {
+	c.message = message
+	return c
+}
+
+// Validate returns an error if the constraint is violated
+func (c URLConstraint) Validate(value interface{}) error {
+	val, ok := value.(string)
+	if !ok {
+		return NewError(ErrNotString, CannotValidateNonStringMessage)
+	}
+	if !c.valid(val) {
+		return NewError(ErrInvalidURL, c.message)
+	}
+	return nil
+}
+
+// valid returns true if rawURL is accepted by the constraint
+func (c URLConstraint) valid(rawURL string) bool {
+	if rawURL == "" || strings.IndexFunc(rawURL, unicode.IsSpace) >= 0 {
+		return false
+	}
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	switch {
+	case parsedURL.Scheme != "":
+		if !c.supports(parsedURL.Scheme) {
+			return false
+		}
+	case strings.HasPrefix(rawURL, "//"):
+		if !c.allowProtocolRelative && !c.allowRelative {
+			return false
+		}
+	default:
+		// relative references have neither a protocol nor a host
+		return c.allowRelative
+	}
+	if parsedURL.Host == "" {
+		return !c.requireHost && parsedURL.Scheme != ""
+	}
+	return c.validHost(parsedURL)
+}
+
+// supports returns true if protocol is one of the protocols of the constraint
+func (c URLConstraint) supports(protocol string) bool {
+	if len(c.protocols) == 0 {
+		return true
+	}
+	for _, supported := range c.protocols {
+		if strings.EqualFold(protocol, supported) {
+			return true
+		}
+	}
+	return false
+}
+
+// validHost returns true if the host of u is a valid domain name or IP
+// address followed by a valid port
+func (c URLConstraint) validHost(u *url.URL) bool {
+	if port := u.Port(); port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return false
+		}
+	} else if strings.HasSuffix(u.Host, ":") {
+		return false
+	}
+	host := u.Hostname()
+	if strings.HasPrefix(u.Host, "[") {
+		// IP literals may have a zone such as fe80::1%25eth0
+		ip := net.ParseIP(strings.SplitN(host, "%", 2)[0])
+		return ip != nil && strings.Contains(host, ":") && !c.requireTLD
+	}
+	labels := strings.Split(host, ".")
+	if isNumeric(labels[len(labels)-1]) {
+		ip := net.ParseIP(host)
+		return ip != nil && ip.To4() != nil && !c.requireTLD
+	}
+	asciiHost, ok := validDomain(strings.TrimSuffix(host, "."))
+	if !ok {
+		return false
+	}
+	if c.requireTLD {
+		labels = strings.Split(asciiHost, ".")
+		tld := labels[len(labels)-1]
+		return len(labels) > 1 && (strings.HasPrefix(tld, acePrefix) || strings.IndexFunc(tld, func(r rune) bool { return r < 'a' || r > 'z' }) < 0)
+	}
+	return true
+}
+
+// isNumeric returns true if s only has decimal digits
+func isNumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
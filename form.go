@@ -0,0 +1,269 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package validator
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/interactiv/validator/constraint"
+)
+
+// TimeLayouts are the layouts of the times bound from forms, the ones of
+// the date, time and datetime-local HTML inputs and RFC 3339
+var TimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02", "15:04:05", "15:04"}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	fileHeaderType      = reflect.TypeOf(&multipart.FileHeader{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// BindForm sets the exported fields of loader, a pointer to a struct, from
+// the values and the files of a form. Fields are named by their form tag or
+// their Go name, strings, booleans, numbers, times, durations, types
+// implementing encoding.TextUnmarshaler, pointers and slices of them as
+// well as *multipart.FileHeader are supported. Empty values leave fields
+// unchanged. Values that cannot be converted are returned as violations
+// of the fields, files being nil for url encoded forms
+func (v *Validator) BindForm(values url.Values, files map[string][]*multipart.FileHeader, loader ValidatorMetadataLoader) []error {
+	errs, _ := v.bind(context.Background(), loader, values, files)
+	return errs
+}
+
+// ValidateForm binds a form to loader and validates it, see BindForm and
+// Validate. Conversion errors and violations are returned in the same list,
+// the constraints of fields that couldn't be converted being skipped
+func (v *Validator) ValidateForm(values url.Values, files map[string][]*multipart.FileHeader, loader ValidatorMetadataLoader) []error {
+	return v.ValidateFormContext(context.Background(), values, files, loader)
+}
+
+// ValidateFormContext is ValidateForm with a context passed to the constraints
+func (v *Validator) ValidateFormContext(ctx context.Context, values url.Values, files map[string][]*multipart.FileHeader, loader ValidatorMetadataLoader) []error {
+	errs, failed := v.bind(ctx, loader, values, files)
+	if failed == nil {
+		return errs
+	}
+	for _, err := range v.ValidateContext(ctx, loader) {
+		if violation, ok := err.(constraint.FieldError); ok && failed[violation.PropertyPath()[0].Name] {
+			continue
+		}
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// CheckForm binds a form to loader and checks it, see ValidateForm and Check
+func (v *Validator) CheckForm(values url.Values, files map[string][]*multipart.FileHeader, loader ValidatorMetadataLoader) error {
+	return v.CheckFormContext(context.Background(), values, files, loader)
+}
+
+// CheckFormContext is CheckForm with a context passed to the constraints
+func (v *Validator) CheckFormContext(ctx context.Context, values url.Values, files map[string][]*multipart.FileHeader, loader ValidatorMetadataLoader) error {
+	return v.check(v.ValidateFormContext(ctx, values, files, loader))
+}
+
+// bind binds a form to loader and returns the errors and the names of the
+// fields whose values couldn't be converted, nil if binding failed. Messages
+// are translated in the locale of ctx
+func (v *Validator) bind(ctx context.Context, loader ValidatorMetadataLoader, values url.Values, files map[string][]*multipart.FileHeader) ([]error, map[string]bool) {
+	metadata, err := v.LoadMetadata(loader)
+	if err != nil {
+		return []error{err}, nil
+	}
+	value := reflect.ValueOf(loader)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return []error{fmt.Errorf("validator: cannot bind a form to %s, a pointer to a struct is expected", value.Type())}, nil
+	}
+	b := &binder{validator: v, metadata: metadata, typeString: value.Type().String(), locale: v.localeOf(ctx), values: values, files: files, failed: map[string]bool{}}
+	b.bindFields(value.Elem())
+	for _, err := range b.errs {
+		if _, ok := err.(constraint.FieldError); !ok {
+			return b.errs, nil
+		}
+	}
+	return b.errs, b.failed
+}
+
+// binder binds the values and files of a form to a struct
+type binder struct {
+	validator  *Validator
+	metadata   *Metadata
+	typeString string
+	locale     string
+	values     url.Values
+	files      map[string][]*multipart.FileHeader
+	errs       []error
+	failed     map[string]bool
+}
+
+// bindFields binds the fields of the struct s, the fields of embedded
+// structs being promoted. Nil embedded pointers are allocated
+func (b *binder) bindFields(s reflect.Value) {
+	t := s.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			b.bindFields(s.Field(i))
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct {
+			if embedded := s.Field(i); !embedded.IsNil() {
+				b.bindFields(embedded.Elem())
+			} else if embedded.CanSet() {
+				embedded.Set(reflect.New(field.Type.Elem()))
+				b.bindFields(embedded.Elem())
+			}
+			continue
+		}
+		key := strings.Split(field.Tag.Get("form"), ",")[0]
+		if field.PkgPath != "" || key == "-" {
+			continue
+		}
+		if key == "" {
+			key = field.Name
+		}
+		fieldValue := s.Field(i)
+		if fieldValue.Type() == fileHeaderType {
+			if len(b.files[key]) > 0 {
+				fieldValue.Set(reflect.ValueOf(b.files[key][0]))
+			}
+			continue
+		}
+		if fieldValue.Type() == reflect.SliceOf(fileHeaderType) {
+			if len(b.files[key]) > 0 {
+				fieldValue.Set(reflect.ValueOf(b.files[key]))
+			}
+			continue
+		}
+		strs := b.values[key]
+		if len(strs) == 0 {
+			continue
+		}
+		if fieldValue.Kind() == reflect.Slice && !reflect.PointerTo(fieldValue.Type()).Implements(textUnmarshalerType) {
+			slice := reflect.MakeSlice(fieldValue.Type(), len(strs), len(strs))
+			for j, str := range strs {
+				b.report(field, fmt.Sprintf("%s[%d]", field.Name, j), str, convert(slice.Index(j), str))
+			}
+			fieldValue.Set(slice)
+			continue
+		}
+		b.report(field, field.Name, strs[0], convert(fieldValue, strs[0]))
+	}
+}
+
+// report records err, the error converting the value str of the field at
+// path. Conversion errors are reported as violations of the field, str
+// being masked if the field is sensitive
+func (b *binder) report(field reflect.StructField, path string, str string, err error) {
+	if err == nil {
+		return
+	}
+	if _, ok := err.(*constraint.Error); !ok {
+		b.errs = append(b.errs, err)
+		return
+	}
+	b.failed[field.Name] = true
+	fieldName := path
+	tagName := b.validator.tagName
+	if name := strings.Split(field.Tag.Get(tagName), ",")[0]; tagName != "" && name != "" && name != "-" {
+		fieldName = name + strings.TrimPrefix(path, field.Name)
+	}
+	sensitive := b.metadata.sensitive[field.Name] || field.Tag.Get("sensitive") == "true"
+	var invalidValue interface{} = str
+	if sensitive {
+		invalidValue = b.validator.redactor.Redact(fieldName, str)
+	}
+	b.errs = append(b.errs, constraint.NewFieldError(fieldName, b.typeString, err).
+		WithLabel(b.metadata.labels[field.Name]).
+		WithInvalidValue(invalidValue, sensitive).
+		Translate(b.validator.translator, b.locale))
+}
+
+// parseBool parses str like strconv.ParseBool, accepting the values "on"
+// and "off" sent by checkboxes
+func parseBool(str string) (bool, error) {
+	switch str {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	return strconv.ParseBool(str)
+}
+
+// convert converts str to the type of v and sets v, empty strings leaving
+// v unchanged unless it is a string
+func convert(v reflect.Value, str string) error {
+	if str == "" && v.Kind() != reflect.String {
+		return nil
+	}
+	switch {
+	case v.Type() == timeType:
+		for _, layout := range TimeLayouts {
+			if t, err := time.Parse(layout, str); err == nil {
+				v.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return constraint.NewError(constraint.ErrInvalidDateTime, constraint.DateTimeMessage)
+	case reflect.PointerTo(v.Type()).Implements(textUnmarshalerType):
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str)); err != nil {
+			return constraint.NewError(constraint.ErrInvalidType, constraint.TypeMessage, v.Type())
+		}
+		return nil
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return constraint.NewError(constraint.ErrInvalidDuration, constraint.DurationMessage)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	var err error
+	switch v.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := convert(elem.Elem(), str); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.String:
+		v.SetString(str)
+	case reflect.Bool:
+		var b bool
+		if b, err = parseBool(str); err == nil {
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(str, 10, v.Type().Bits()); err == nil {
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		if u, err = strconv.ParseUint(str, 10, v.Type().Bits()); err == nil {
+			v.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(str, v.Type().Bits()); err == nil {
+			v.SetFloat(f)
+		}
+	default:
+		return fmt.Errorf("validator: cannot bind a form value to %s", v.Type())
+	}
+	if err != nil {
+		return constraint.NewError(constraint.ErrInvalidType, constraint.TypeMessage, v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package validator_test
+
+import (
+	"mime/multipart"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator"
+	"github.com/interactiv/validator/constraint"
+)
+
+type Booking struct {
+	Name     string        `form:"name"`
+	Guests   int           `form:"guests"`
+	Pets     bool          `form:"pets"`
+	Arrival  time.Time     `form:"arrival"`
+	Stay     time.Duration `form:"stay"`
+	Rooms    []int         `form:"rooms"`
+	Discount *float64      `form:"discount"`
+	Address  net.IP        `form:"ip"`
+	Code     string        `form:"code" sensitive:"true"`
+	PIN      int           `form:"pin" sensitive:"true"`
+	Passport *multipart.FileHeader
+	Ignored  string `form:"-"`
+}
+
+func (b *Booking) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Name", constraint.NotBlank()).
+		AddFieldConstraint("Guests", constraint.Range(1, 4)).
+		SetFieldLabel("Guests", "The number of guests")
+}
+
+func TestBindForm(t *testing.T) {
+	e := expect.New(t)
+	booking := &Booking{}
+	passport := &multipart.FileHeader{Filename: "passport.pdf"}
+	Errors := validator.New().BindForm(url.Values{
+		"name": {"John"}, "guests": {"2"}, "pets": {"on"}, "arrival": {"2015-06-15"}, "stay": {"72h"},
+		"rooms": {"12", "14"}, "discount": {"0.1"}, "ip": {"192.168.0.1"}, "Ignored": {"value"},
+	}, map[string][]*multipart.FileHeader{"Passport": {passport}}, booking)
+	e.Expect(len(Errors)).ToBe(0)
+	e.Expect(booking.Name).ToBe("John")
+	e.Expect(booking.Guests).ToBe(2)
+	e.Expect(booking.Pets).ToBe(true)
+	e.Expect(booking.Arrival.Equal(time.Date(2015, time.June, 15, 0, 0, 0, 0, time.UTC))).ToBe(true)
+	e.Expect(booking.Stay).ToBe(72 * time.Hour)
+	e.Expect(booking.Rooms).ToBe([]int{12, 14})
+	e.Expect(*booking.Discount).ToBe(0.1)
+	e.Expect(booking.Address.String()).ToBe("192.168.0.1")
+	e.Expect(booking.Passport).ToBe(passport)
+	e.Expect(booking.Ignored).ToBe("")
+	// checkboxes send on and off
+	validator.New().BindForm(url.Values{"pets": {"off"}}, nil, booking)
+	e.Expect(booking.Pets).ToBe(false)
+}
+
+type Review struct {
+	*BaseEntity
+	Rating int
+}
+
+func (r *Review) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Rating", constraint.Range(1, 5))
+}
+
+func TestBindFormEmbedded(t *testing.T) {
+	e := expect.New(t)
+	review := &Review{}
+	Errors := validator.New().BindForm(url.Values{"ID": {"1"}, "CreatedBy": {"john"}, "Rating": {"4"}}, nil, review)
+	e.Expect(len(Errors)).ToBe(0)
+	e.Expect(review.BaseEntity == nil).ToBe(false)
+	e.Expect(review.ID).ToBe(1)
+	e.Expect(review.CreatedBy).ToBe("john")
+}
+
+func TestValidateForm(t *testing.T) {
+	e := expect.New(t)
+	Errors := validator.New().ValidateForm(url.Values{
+		"guests": {"two"}, "arrival": {"tomorrow"}, "rooms": {"12", "B"}, "pin": {"12a4"}, "discount": {""},
+	}, nil, &Booking{})
+	e.Expect(len(Errors)).ToBe(5)
+	violations := validator.Violations(Errors)
+	e.Expect(len(violations)).ToBe(5)
+	e.Expect(violations[0].FieldName()).ToBe("Guests")
+	e.Expect(violations[0].Code()).ToBe(constraint.ErrInvalidType)
+	e.Expect(violations[0].Error()).ToBe("The number of guests should be of type int")
+	e.Expect(violations[0].InvalidValue()).ToBe("two")
+	e.Expect(violations[1].Code()).ToBe(constraint.ErrInvalidDateTime)
+	e.Expect(violations[2].FieldName()).ToBe("Rooms[1]")
+	e.Expect(violations[3].FieldName()).ToBe("PIN")
+	e.Expect(violations[3].InvalidValue()).ToBe(constraint.RedactedValue)
+	// the constraints of Guests are skipped, Name is validated
+	e.Expect(violations[4].FieldName()).ToBe("Name")
+	e.Expect(violations[4].Code()).ToBe(constraint.ErrBlank)
+
+	err := validator.New().SetTagName("form").CheckForm(url.Values{"name": {"John"}, "guests": {"9"}}, nil, &Booking{})
+	e.Expect(err.Error()).ToBe("guests: The number of guests should be 4 or less")
+}
@@ -0,0 +1,243 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+// Package httpvalidate provides a net/http middleware decoding the body of
+// requests into a value, validating it and responding with a problem
+// details document when it is invalid
+//
+//	handler := httpvalidate.New(validator.New(), &Signup{}).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//		signup := httpvalidate.FromContext(r.Context()).(*Signup)
+//		...
+//	}))
+package httpvalidate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/interactiv/validator"
+)
+
+// DefaultMaxBodySize is the default limit of the size of request bodies
+const DefaultMaxBodySize = 1 << 20
+
+type contextKey struct{}
+
+// FromContext returns the validated value passed by the middleware to the
+// next handler, a pointer to a value of the type of the target
+func FromContext(ctx context.Context) interface{} {
+	return ctx.Value(contextKey{})
+}
+
+// NewContext returns a copy of ctx carrying value, useful to test handlers
+// without the middleware
+func NewContext(ctx context.Context, value interface{}) context.Context {
+	return context.WithValue(ctx, contextKey{}, value)
+}
+
+// Middleware decodes JSON and form bodies into new values of the type of
+// its target and validates them
+type Middleware struct {
+	validator   *validator.Validator
+	target      reflect.Type
+	maxBodySize int64
+}
+
+// New returns a middleware decoding request bodies into new values of the
+// type of target, a pointer to a struct, and validating them with v
+func New(v *validator.Validator, target validator.ValidatorMetadataLoader) *Middleware {
+	t := reflect.TypeOf(target)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return &Middleware{validator: v, target: t, maxBodySize: DefaultMaxBodySize}
+}
+
+// MaxBodySize returns the limit of the size of request bodies
+func (m *Middleware) MaxBodySize() int64 {
+	return m.maxBodySize
+}
+
+// SetMaxBodySize sets the limit of the size of request bodies, larger
+// bodies are rejected with the 413 Request Entity Too Large status
+func (m *Middleware) SetMaxBodySize(maxBodySize int64) *Middleware {
+	m.maxBodySize = maxBodySize
+	return m
+}
+
+// Handler returns a handler decoding and validating the body of requests,
+// form values that cannot be converted being reported as violations and
+// messages being rendered in the language of the client, see Localize. Valid
+// values are passed to next in the context of the request, see FromContext,
+// invalid ones are answered with a 422 problem details response listing
+// the violations
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return Localize(m.validator, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := reflect.New(m.target).Interface().(validator.ValidatorMetadataLoader)
+		err := m.decode(w, r, value)
+		var statusErr *statusError
+		if errors.As(err, &statusErr) {
+			writeProblem(w, r, newProblem(statusErr.status, err.Error()), nil)
+			return
+		}
+		// violations joined with system errors are answered with a 500
+		validationError, ok := err.(*validator.ValidationError)
+		switch {
+		case ok:
+			problem := validationError.Violations().Problem()
+			problem.Instance = r.URL.Path
+			writeProblem(w, r, problem, validationError.Violations())
+			return
+		case err != nil:
+			writeProblem(w, r, newProblem(http.StatusInternalServerError, ""), nil)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), value)))
+	}))
+}
+
+// Localize returns a handler passing to next requests whose context carries
+// the locale of v preferred by their Accept-Language header, see
+// validator.WithLocale. The locale is announced in the Content-Language
+// header of the response
+func Localize(v *validator.Validator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := v.NegotiateLocale(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", locale)
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r.WithContext(validator.WithLocale(r.Context(), locale)))
+	})
+}
+
+// statusError is an error answered with an HTTP status
+type statusError struct {
+	status int
+	err    error
+}
+
+func (se *statusError) Error() string {
+	return se.err.Error()
+}
+
+func (se *statusError) Unwrap() error {
+	return se.err
+}
+
+// decode decodes the body of r into value according to its content type
+// and checks it. Form values that cannot be converted are reported as
+// violations, other decoding errors as *statusError
+func (m *Middleware) decode(w http.ResponseWriter, r *http.Request, value validator.ValidatorMetadataLoader) error {
+	r.Body = http.MaxBytesReader(w, r.Body, m.maxBodySize)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	var err error
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		decoder := json.NewDecoder(r.Body)
+		if err = decoder.Decode(value); err == nil && decoder.More() {
+			err = errors.New("unexpected data after the JSON value")
+		}
+		if err == nil {
+			return m.validator.CheckContext(r.Context(), value)
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		if err = r.ParseForm(); err == nil {
+			return m.validator.CheckFormContext(r.Context(), r.PostForm, nil, value)
+		}
+	case mediaType == "multipart/form-data":
+		if err = r.ParseMultipartForm(m.maxBodySize); err == nil {
+			return m.validator.CheckFormContext(r.Context(), r.MultipartForm.Value, r.MultipartForm.File, value)
+		}
+	default:
+		return &statusError{http.StatusUnsupportedMediaType, fmt.Errorf("unsupported content type %q", mediaType)}
+	}
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		return &statusError{http.StatusRequestEntityTooLarge, fmt.Errorf("request body larger than %d bytes", maxBytesError.Limit)}
+	}
+	return &statusError{http.StatusBadRequest, err}
+}
+
+// newProblem returns a problem details document for status
+func newProblem(status int, detail string) *validator.Problem {
+	return &validator.Problem{Type: "about:blank", Title: http.StatusText(status), Status: status, Detail: detail}
+}
+
+// writeProblem writes problem in the media type preferred by the client,
+// application/problem+json, application/json or text/plain
+func writeProblem(w http.ResponseWriter, r *http.Request, problem *validator.Problem, violations validator.ViolationList) {
+	mediaType := negotiate(r.Header.Get("Accept"), validator.ProblemContentType, "application/json", "text/plain")
+	w.Header().Set("Content-Type", mediaType+"; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+	if mediaType == "text/plain" {
+		fmt.Fprintln(w, problem.Title)
+		if problem.Detail != "" {
+			fmt.Fprintln(w, problem.Detail)
+		}
+		if len(violations) > 0 {
+			fmt.Fprintln(w, violations.Error())
+		}
+		return
+	}
+	json.NewEncoder(w).Encode(problem)
+}
+
+// negotiate returns the offer preferred by the Accept header, the first
+// offer when none is acceptable
+func negotiate(accept string, offers ...string) string {
+	type candidate struct {
+		offer       string
+		q           float64
+		specificity int
+	}
+	candidates := []candidate{}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		for _, offer := range offers {
+			specificity := 0
+			switch {
+			case mediaType == offer:
+				specificity = 2
+			case strings.HasSuffix(mediaType, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(mediaType, "*")):
+				specificity = 1
+			case mediaType != "*/*":
+				continue
+			}
+			candidates = append(candidates, candidate{offer, q, specificity})
+		}
+	}
+	// the most specific range matching an offer gives its quality
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].specificity > candidates[j].specificity
+	})
+	qualities := map[string]float64{}
+	for _, c := range candidates {
+		if _, ok := qualities[c.offer]; !ok {
+			qualities[c.offer] = c.q
+		}
+	}
+	best, bestQ := offers[0], 0.0
+	for _, offer := range offers {
+		if q, ok := qualities[offer]; ok && q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
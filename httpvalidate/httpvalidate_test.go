@@ -0,0 +1,117 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package httpvalidate_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator"
+	"github.com/interactiv/validator/constraint"
+	"github.com/interactiv/validator/httpvalidate"
+)
+
+type Signup struct {
+	Email string `json:"email" form:"email"`
+	Age   int    `json:"age" form:"age"`
+}
+
+func (s *Signup) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Email", constraint.Email()).
+		AddFieldConstraint("Age", constraint.GreaterThanOrEqual(18))
+}
+
+func serve(body string, contentType string, accept string, acceptLanguage ...string) *httptest.ResponseRecorder {
+	catalog := constraint.NewCatalog().Add("fr", constraint.EmailMessage, "Cette valeur n'est pas une adresse email valide")
+	handler := httpvalidate.New(validator.New().SetTagName("json").SetTranslator(catalog), &Signup{}).SetMaxBodySize(64).
+		Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			signup := httpvalidate.FromContext(r.Context()).(*Signup)
+			w.Write([]byte(signup.Email))
+		}))
+	request := httptest.NewRequest("POST", "/signup", strings.NewReader(body))
+	request.Header.Set("Content-Type", contentType)
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	for _, language := range acceptLanguage {
+		request.Header.Add("Accept-Language", language)
+	}
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response
+}
+
+func TestMiddleware(t *testing.T) {
+	e := expect.New(t)
+	response := serve(`{"email":"john@example.com","age":20}`, "application/json", "")
+	e.Expect(response.Code).ToBe(http.StatusOK)
+	e.Expect(response.Body.String()).ToBe("john@example.com")
+
+	response = serve(url.Values{"email": {"jane@example.com"}, "age": {"30"}}.Encode(), "application/x-www-form-urlencoded", "")
+	e.Expect(response.Code).ToBe(http.StatusOK)
+	e.Expect(response.Body.String()).ToBe("jane@example.com")
+
+	response = serve(`{"email":"john","age":12}`, "application/json", "")
+	e.Expect(response.Code).ToBe(http.StatusUnprocessableEntity)
+	e.Expect(response.Header().Get("Content-Type")).ToBe("application/problem+json; charset=utf-8")
+	problem := &validator.Problem{}
+	e.Expect(json.Unmarshal(response.Body.Bytes(), problem) == nil).ToBe(true)
+	e.Expect(problem.Status).ToBe(http.StatusUnprocessableEntity)
+	e.Expect(problem.Instance).ToBe("/signup")
+	e.Expect(len(problem.InvalidParams)).ToBe(2)
+	e.Expect(problem.InvalidParams[0].Name).ToBe("email")
+	e.Expect(problem.InvalidParams[1].Code).ToBe("TOO_LOW")
+
+	response = serve(`{"email":"john","age":12}`, "application/json", "application/json")
+	e.Expect(response.Header().Get("Content-Type")).ToBe("application/json; charset=utf-8")
+	response = serve(`{"email":"john","age":12}`, "application/json", "text/*;q=0.9, application/json;q=0.5")
+	e.Expect(response.Header().Get("Content-Type")).ToBe("text/plain; charset=utf-8")
+	e.Expect(strings.Contains(response.Body.String(), "email: This value is not a valid email address")).ToBe(true)
+
+	response = serve(`{"email":"john","age":20}`, "application/json", "", "fr-CA, en;q=0.5")
+	e.Expect(response.Header().Get("Content-Language")).ToBe("fr")
+	e.Expect(json.Unmarshal(response.Body.Bytes(), problem) == nil).ToBe(true)
+	e.Expect(problem.InvalidParams[0].Reason).ToBe("Cette valeur n'est pas une adresse email valide")
+
+	e.Expect(serve(`{"email":`, "application/json", "").Code).ToBe(http.StatusBadRequest)
+	response = serve(`email=john@example.com&age=old`, "application/x-www-form-urlencoded", "")
+	e.Expect(response.Code).ToBe(http.StatusUnprocessableEntity)
+	e.Expect(json.Unmarshal(response.Body.Bytes(), problem) == nil).ToBe(true)
+	e.Expect(len(problem.InvalidParams)).ToBe(1)
+	e.Expect(problem.InvalidParams[0].Name).ToBe("age")
+	e.Expect(problem.InvalidParams[0].Code).ToBe("INVALID_TYPE")
+	e.Expect(serve(`<signup/>`, "application/xml", "").Code).ToBe(http.StatusUnsupportedMediaType)
+	e.Expect(serve(`{"email":"`+strings.Repeat("a", 64)+`@example.com"}`, "application/json", "").Code).ToBe(http.StatusRequestEntityTooLarge)
+}
+
+type Subscriber struct {
+	Email string `json:"email"`
+}
+
+func (s *Subscriber) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Email", constraint.Email()).
+		AddFieldConstraint("Email", constraint.Unique(constraint.LookupFunc(func(ctx context.Context, value interface{}) (bool, error) {
+			return false, errors.New("connection refused")
+		})))
+}
+
+func TestMiddlewareSystemError(t *testing.T) {
+	e := expect.New(t)
+	handler := httpvalidate.New(validator.New().SetTagName("json"), &Subscriber{}).
+		Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	// violations found along with a failing lookup aren't answered with a 422
+	request := httptest.NewRequest("POST", "/subscribe", strings.NewReader(`{"email":"john"}`))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	e.Expect(response.Code).ToBe(http.StatusInternalServerError)
+	e.Expect(strings.Contains(response.Body.String(), "connection refused")).ToBe(false)
+}
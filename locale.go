@@ -0,0 +1,93 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package validator
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx in which ValidateContext and CheckContext
+// render messages in locale, the locale of the validator being used otherwise
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale of ctx, an empty string if it has none
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// NegotiateLocale returns the locale of locales preferred by an
+// Accept-Language header, such as "fr-CA, fr;q=0.9, en;q=0.5", or fallback
+// when none is acceptable. Languages are tried by decreasing quality, each
+// one falling back to its less specific tags, "fr-CA" then "fr"
+func NegotiateLocale(acceptLanguage string, locales []string, fallback string) string {
+	type language struct {
+		tag string
+		q   float64
+	}
+	languages := []language{}
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		params := strings.Split(part, ";")
+		tag := strings.TrimSpace(params[0])
+		q := 1.0
+		for _, param := range params[1:] {
+			if value := strings.TrimSpace(param); strings.HasPrefix(value, "q=") {
+				var err error
+				if q, err = strconv.ParseFloat(strings.TrimPrefix(value, "q="), 64); err != nil {
+					q = 0
+				}
+			}
+		}
+		if tag != "" && q > 0 {
+			languages = append(languages, language{tag, q})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].q > languages[j].q
+	})
+	for _, language := range languages {
+		if language.tag == "*" {
+			return fallback
+		}
+		for tag := strings.Replace(language.tag, "_", "-", -1); tag != ""; {
+			for _, locale := range locales {
+				if strings.EqualFold(strings.Replace(locale, "_", "-", -1), tag) {
+					return locale
+				}
+			}
+			i := strings.LastIndex(tag, "-")
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+	}
+	return fallback
+}
+
+// NegotiateLocale returns the locale of the translator of the validator
+// preferred by an Accept-Language header, the locale of the validator when
+// none is acceptable
+func (v *Validator) NegotiateLocale(acceptLanguage string) string {
+	if v.translator == nil {
+		return v.locale
+	}
+	return NegotiateLocale(acceptLanguage, append(v.translator.Locales(), v.locale), v.locale)
+}
+
+// localeOf returns the locale of ctx, the locale of the validator if ctx
+// has none
+func (v *Validator) localeOf(ctx context.Context) string {
+	if locale := LocaleFromContext(ctx); locale != "" {
+		return locale
+	}
+	return v.locale
+}
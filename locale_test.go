@@ -0,0 +1,57 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator"
+	"github.com/interactiv/validator/constraint"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	e := expect.New(t)
+	locales := []string{"en", "fr", "pt_BR"}
+	e.Expect(validator.NegotiateLocale("fr-CA, en;q=0.5", locales, "en")).ToBe("fr")
+	e.Expect(validator.NegotiateLocale("de, en;q=0.8, fr;q=0.9", locales, "en")).ToBe("fr")
+	e.Expect(validator.NegotiateLocale("pt-br", locales, "en")).ToBe("pt_BR")
+	e.Expect(validator.NegotiateLocale("de, fr;q=0", locales, "en")).ToBe("en")
+	e.Expect(validator.NegotiateLocale("", locales, "en")).ToBe("en")
+	e.Expect(validator.NegotiateLocale("de, *;q=0.5", locales, "fr")).ToBe("fr")
+}
+
+func TestTranslation(t *testing.T) {
+	e := expect.New(t)
+	catalog := constraint.NewCatalog().
+		Add("fr", constraint.NotBlankMessage, "{{ label }} ne doit pas être vide").
+		Add("fr", constraint.TypeMessage, "{{ label }} doit être de type %s").
+		Add("fr", constraint.DefaultLabel, "Cette valeur").
+		Add("fr", "Email address", "L'adresse email")
+	Validator := validator.New().SetTranslator(catalog)
+	e.Expect(Validator.NegotiateLocale("fr-CA,fr;q=0.9")).ToBe("fr")
+	e.Expect(Validator.NegotiateLocale("de")).ToBe("en")
+
+	ctx := validator.WithLocale(context.Background(), Validator.NegotiateLocale("fr-CA"))
+	Errors := Validator.ValidateContext(ctx, &Person{IsMarried: true})
+	e.Expect(Errors[0].Error()).ToBe("Cette valeur ne doit pas être vide")
+	e.Expect(errors.Is(Errors[0], constraint.ErrBlank)).ToBe(true)
+	Errors = Validator.ValidateContext(ctx, &Person{Name: "John"})
+	e.Expect(Errors[0].Error()).ToBe("This value should be true")
+	Errors = Validator.Validate(&Person{IsMarried: true})
+	e.Expect(Errors[0].Error()).ToBe("This value should not be blank")
+
+	Errors = Validator.ValidateFormContext(ctx, map[string][]string{"name": {"John"}, "guests": {"two"}}, nil, &Booking{})
+	e.Expect(Errors[0].Error()).ToBe("The number of guests doit être de type int")
+
+	// labels are rendered in translated messages, and translated when the
+	// catalog has them
+	Errors = Validator.ValidateContext(ctx, &Signup{Contact: &Contact{Phone: "555"}})
+	e.Expect(Errors[0].Error()).ToBe("L'adresse email ne doit pas être vide")
+	e.Expect(Errors[1].Error()).ToBe("Cette valeur ne doit pas être vide")
+	Errors = Validator.Validate(&Signup{Contact: &Contact{Phone: "555"}})
+	e.Expect(Errors[0].Error()).ToBe("Email address should not be blank")
+}
@@ -0,0 +1,128 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package validator
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemContentType is the media type of problem details documents
+const ProblemContentType = "application/problem+json"
+
+// Violations returns the violations found in errs, the errors returned by
+// Validate, other errors being ignored
+func Violations(errs []error) ViolationList {
+	violations, _ := newViolationList(errs)
+	return violations
+}
+
+// violationJSON is the JSON representation of a violation
+type violationJSON struct {
+	Path     string                 `json:"path"`
+	Code     string                 `json:"code"`
+	Message  string                 `json:"message"`
+	Params   []interface{}          `json:"params"`
+	Severity string                 `json:"severity"`
+	Value    interface{}            `json:"value,omitempty"`
+	Payload  map[string]interface{} `json:"payload,omitempty"`
+}
+
+// MarshalJSON returns the violations as a JSON array of objects with the
+// path, code, message, params and severity of each violation, and its
+// payload when it has one. Invalid values, which may hold personal data,
+// are omitted, see WithValues
+func (vl ViolationList) MarshalJSON() ([]byte, error) {
+	return marshalViolations(vl, false)
+}
+
+// WithValues returns the violations marshaled to JSON with their invalid
+// values, the values of sensitive fields being masked
+func (vl ViolationList) WithValues() ViolationsWithValues {
+	return ViolationsWithValues(vl)
+}
+
+// ViolationsWithValues is a list of violations marshaled to JSON with
+// their invalid values, see ViolationList.WithValues
+type ViolationsWithValues ViolationList
+
+// MarshalJSON returns the violations as a JSON array like
+// ViolationList.MarshalJSON, each violation having its invalid value
+func (vv ViolationsWithValues) MarshalJSON() ([]byte, error) {
+	return marshalViolations(ViolationList(vv), true)
+}
+
+// marshalViolations returns vl as a JSON array, with the invalid values of
+// the violations if withValues is true
+func marshalViolations(vl ViolationList, withValues bool) ([]byte, error) {
+	violations := []violationJSON{}
+	for _, violation := range vl {
+		params := violation.Params()
+		if params == nil {
+			params = []interface{}{}
+		}
+		rendered := violationJSON{
+			Path:     violation.FieldName(),
+			Code:     string(violation.Code()),
+			Message:  violation.Error(),
+			Params:   params,
+			Severity: violation.Severity().String(),
+			Payload:  violation.Payload(),
+		}
+		if withValues {
+			rendered.Value = violation.InvalidValue()
+		}
+		violations = append(violations, rendered)
+	}
+	return json.Marshal(violations)
+}
+
+// ToMap returns the messages of the violations keyed by property path
+func (vl ViolationList) ToMap() map[string][]string {
+	messages := map[string][]string{}
+	for _, violation := range vl {
+		messages[violation.FieldName()] = append(messages[violation.FieldName()], violation.Error())
+	}
+	return messages
+}
+
+// InvalidParam is a member of the invalid-params extension of a problem
+// details document
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	Code   string `json:"code,omitempty"`
+}
+
+// Problem is a problem details document as defined by RFC 9457, which
+// obsoletes RFC 7807, with the invalid-params extension
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// Problem returns a problem details document listing the violations as
+// invalid params, with the 422 Unprocessable Entity status. Its type and
+// title can be changed, as well as its detail and instance members
+func (vl ViolationList) Problem() *Problem {
+	problem := &Problem{
+		Type:          "about:blank",
+		Title:         http.StatusText(http.StatusUnprocessableEntity),
+		Status:        http.StatusUnprocessableEntity,
+		Detail:        "Your request parameters didn't validate.",
+		InvalidParams: []InvalidParam{},
+	}
+	for _, violation := range vl {
+		problem.InvalidParams = append(problem.InvalidParams, InvalidParam{
+			Name:   violation.FieldName(),
+			Reason: violation.Error(),
+			Code:   string(violation.Code()),
+		})
+	}
+	return problem
+}
@@ -0,0 +1,36 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package validator_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/interactiv/expect"
+	"github.com/interactiv/validator"
+)
+
+func TestRenderers(t *testing.T) {
+	e := expect.New(t)
+	violations := validator.Violations(validator.New().Validate(&Payment{Email: "john@example.com", CardNumber: "4111111111111111", Password: "hunter2"}))
+	e.Expect(len(violations)).ToBe(1)
+	data, err := json.Marshal(violations)
+	e.Expect(err == nil).ToBe(true)
+	e.Expect(string(data)).ToBe(`[{"path":"Password","code":"TOO_SHORT","message":"This value is too short. It should have 12 characters or more.","params":[12],"severity":"error"}]`)
+	data, _ = json.Marshal(validator.Violations(validator.New().Validate(&Payment{Email: "john", CardNumber: "4111111111111111", Password: "correct horse battery staple"})))
+	e.Expect(string(data)).ToBe(`[{"path":"Email","code":"INVALID_EMAIL","message":"This value is not a valid email address","params":[],"severity":"error"}]`)
+	data, _ = json.Marshal(violations.WithValues())
+	e.Expect(string(data)).ToBe(`[{"path":"Password","code":"TOO_SHORT","message":"This value is too short. It should have 12 characters or more.","params":[12],"severity":"error","value":"********"}]`)
+	data, _ = json.Marshal(validator.ViolationList{})
+	e.Expect(string(data)).ToBe(`[]`)
+
+	messages := validator.Violations(validator.New().Validate(&Person{})).ToMap()
+	e.Expect(messages["Name"]).ToBe([]string{"This value should not be blank"})
+	e.Expect(messages["IsMarried"]).ToBe([]string{"This value should be true"})
+
+	problem := validator.Violations(validator.New().Validate(&Person{})).Problem()
+	problem.Instance = "/people"
+	data, _ = json.Marshal(problem)
+	e.Expect(string(data)).ToBe(`{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Your request parameters didn't validate.","instance":"/people","invalid-params":[{"name":"Name","reason":"This value should not be blank","code":"IS_BLANK"},{"name":"IsMarried","reason":"This value should be true","code":"NOT_TRUE"}]}`)
+}
@@ -5,31 +5,200 @@
 package validator
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
 	"github.com/interactiv/validator/constraint"
 )
 
 type ValidatorMetadataLoader interface {
 	LoadValidatorMetadata(metadata *Metadata)
 }
-type Validator struct{}
+type Validator struct {
+	factory        constraint.ConstraintValidatorFactory
+	clock          constraint.Clock
+	tagName        string
+	ignoreWarnings bool
+	redactor       constraint.Redactor
+	translator     constraint.Translator
+	locale         string
+}
 
 func New() *Validator {
-	return &Validator{}
+	return &Validator{
+		factory:  constraint.NewConstraintValidatorFactory(),
+		clock:    constraint.SystemClock,
+		redactor: constraint.DefaultRedactor,
+		locale:   "en",
+	}
+}
+
+// Clock returns the clock used to resolve relative times
+func (v *Validator) Clock() constraint.Clock {
+	return v.clock
+}
+
+// SetClock sets the clock used to resolve relative times such as "now"
+// or "-18 years", a fixed clock makes validation deterministic in tests
+func (v *Validator) SetClock(clock constraint.Clock) *Validator {
+	v.clock = clock
+	return v
+}
+
+// TagName returns the struct tag naming fields in property paths
+func (v *Validator) TagName() string {
+	return v.tagName
+}
+
+// SetTagName sets the struct tag, such as json, form or yaml, naming the
+// fields in the property paths of errors. Go names are used for fields
+// without the tag, and for all fields when tagName is empty
+func (v *Validator) SetTagName(tagName string) *Validator {
+	v.tagName = tagName
+	return v
+}
+
+// Redactor returns the redactor masking the values of sensitive fields
+func (v *Validator) Redactor() constraint.Redactor {
+	return v.redactor
+}
+
+// SetRedactor sets the redactor masking the invalid values of the fields
+// marked as sensitive, constraint.DefaultRedactor by default
+func (v *Validator) SetRedactor(redactor constraint.Redactor) *Validator {
+	v.redactor = redactor
+	return v
+}
+
+// Translator returns the translator of the messages of violations
+func (v *Validator) Translator() constraint.Translator {
+	return v.translator
+}
+
+// SetTranslator sets the translator of the messages of violations, such
+// as a constraint.Catalog
+func (v *Validator) SetTranslator(translator constraint.Translator) *Validator {
+	v.translator = translator
+	return v
+}
+
+// Locale returns the default locale of the messages of violations
+func (v *Validator) Locale() string {
+	return v.locale
+}
+
+// SetLocale sets the default locale of the messages of violations, "en" by
+// default. The locale of a validation is chosen per request with WithLocale
+func (v *Validator) SetLocale(locale string) *Validator {
+	v.locale = locale
+	return v
+}
+
+// IgnoreWarnings returns true if only the violations of severity error fail Check
+func (v *Validator) IgnoreWarnings() bool {
+	return v.ignoreWarnings
+}
+
+// SetIgnoreWarnings sets whether Check only fails on violations of
+// severity error, warnings and infos being then accepted. All violations
+// are still returned by Validate
+func (v *Validator) SetIgnoreWarnings(ignoreWarnings bool) *Validator {
+	v.ignoreWarnings = ignoreWarnings
+	return v
+}
+
+// ConstraintValidatorFactory returns the factory resolving the validators
+// of delegating constraints
+func (v *Validator) ConstraintValidatorFactory() constraint.ConstraintValidatorFactory {
+	return v.factory
+}
+
+// SetConstraintValidatorFactory sets the factory resolving the validators
+// of delegating constraints, services needed by those validators are
+// injected in the factory when wiring the application
+func (v *Validator) SetConstraintValidatorFactory(factory constraint.ConstraintValidatorFactory) *Validator {
+	v.factory = factory
+	return v
+}
+
+// LoadMetadata loads the metadata of loader and checks it against the type
+// of loader. The metadata is loaded for each value since it may depend on
+// its state, it returns a *MetadataError if the metadata is invalid
+func (v *Validator) LoadMetadata(loader ValidatorMetadataLoader) (*Metadata, error) {
+	metadata := loadMetadata(loader, map[reflect.Type]bool{})
+	if err := metadata.compile(reflect.TypeOf(loader)); err != nil {
+		return nil, err
+	}
+	return metadata, nil
 }
 
 func (v *Validator) Validate(loader ValidatorMetadataLoader) (errors []error) {
-	metadata := &Metadata{constraints: []constraint.Constraint{}}
-	loader.LoadValidatorMetadata(metadata)
+	return v.ValidateContext(context.Background(), loader)
+}
+
+// ValidateContext validates loader, ctx being passed to constraints
+// querying external services such as a database. Invalid metadata is
+// reported as a *MetadataError
+func (v *Validator) ValidateContext(ctx context.Context, loader ValidatorMetadataLoader) (errors []error) {
+	metadata, err := v.LoadMetadata(loader)
+	if err != nil {
+		return []error{err}
+	}
+	executionContext := constraint.NewExecutionContext().SetFactory(v.factory).SetClock(v.clock).SetTagName(v.tagName).
+		SetRedactor(v.redactor).SetTranslator(v.translator).SetLocale(v.localeOf(ctx)).SetContext(ctx)
 	for _, Constraint := range metadata.constraints {
-		if err := Constraint.Validate(loader); err != nil {
-			errors = append(errors, err)
+		if err := constraint.Execute(executionContext, Constraint, loader); err != nil {
+			if list, ok := err.(constraint.Errors); ok {
+				errors = append(errors, list...)
+			} else {
+				errors = append(errors, err)
+			}
 		}
 	}
 	return errors
 }
 
+// Check validates loader and returns nil if it is valid, a *ValidationError
+// holding the violations sorted by declaration then path otherwise. Invalid
+// metadata and system errors, such as the *constraint.LookupError of a
+// failing lookup, are joined by errors.Join with the *ValidationError of the
+// violations found, if any, so that callers can tell them apart.
+// When warnings are ignored, a value without violations of severity error
+// is valid
+func (v *Validator) Check(loader ValidatorMetadataLoader) error {
+	return v.CheckContext(context.Background(), loader)
+}
+
+// CheckContext is Check with a context passed to the constraints
+func (v *Validator) CheckContext(ctx context.Context, loader ValidatorMetadataLoader) error {
+	return v.check(v.ValidateContext(ctx, loader))
+}
+
+// check returns the error reported by Check for errs
+func (v *Validator) check(errs []error) error {
+	violations, others := newViolationList(errs)
+	if len(violations) > 0 && !(v.ignoreWarnings && len(violations.Errors()) == 0) {
+		others = append(others, &ValidationError{violations: violations.Sort()})
+	}
+	switch len(others) {
+	case 0:
+		return nil
+	case 1:
+		return others[0]
+	}
+	return errors.Join(others...)
+}
+
 type Metadata struct {
+	structType  reflect.Type
+	extended    map[reflect.Type]bool
+	own         []constraint.Constraint
 	constraints []constraint.Constraint
+	labels      map[string]string
+	sensitive   map[string]bool
 }
 
 func (m *Metadata) AddFieldConstraint(field string, Constraint constraint.Constraint) *Metadata {
@@ -37,9 +206,321 @@ func (m *Metadata) AddFieldConstraint(field string, Constraint constraint.Constr
 	return m
 }
 
+// SetFieldLabel sets the label of a field, rendered instead of
+// constraint.DefaultLabel in the messages of its violations such as
+// "Email address should not be blank", see constraint.LabelPlaceholder
+func (m *Metadata) SetFieldLabel(field string, label string) *Metadata {
+	if m.labels == nil {
+		m.labels = map[string]string{}
+	}
+	m.labels[field] = label
+	return m
+}
+
+// SetFieldSensitive marks a field as sensitive, its invalid values being
+// masked in violations. Fields can also be marked with the struct tag
+// `sensitive:"true"`
+func (m *Metadata) SetFieldSensitive(field string) *Metadata {
+	if m.sensitive == nil {
+		m.sensitive = map[string]bool{}
+	}
+	m.sensitive[field] = true
+	return m
+}
+
+// RemoveFieldConstraints removes the constraints of a field, including
+// the inherited ones, so that they can be overridden
+func (m *Metadata) RemoveFieldConstraints(field string) *Metadata {
+	constraints := []constraint.Constraint{}
+	for _, Constraint := range m.constraints {
+		if fieldConstraint, ok := Constraint.(*constraint.FieldConstraint); !ok || fieldConstraint.FieldName() != field {
+			constraints = append(constraints, Constraint)
+		}
+	}
+	m.constraints = constraints
+	return m
+}
+
+// Extend inherits the metadata of parent, a type sharing the fields of the
+// type whose metadata is loaded. The metadata of embedded structs is
+// inherited without calling Extend, extending a parent already inherited has
+// no effect
+func (m *Metadata) Extend(parent ValidatorMetadataLoader) *Metadata {
+	parentType := indirect(reflect.TypeOf(parent))
+	if m.extended[parentType] {
+		return m
+	}
+	inherited := loadMetadata(parent, map[reflect.Type]bool{})
+	if field, ok := embeddedField(m.structType, parentType); ok {
+		inherited = inherited.promote(m.structType, field)
+	}
+	m.inherit(inherited)
+	m.extended[parentType] = true
+	return m
+}
+
+// inherit appends the constraints, labels and sensitive fields of parent
+func (m *Metadata) inherit(parent *Metadata) {
+	m.constraints = append(m.constraints, parent.constraints...)
+	for field, label := range parent.labels {
+		m.SetFieldLabel(field, label)
+	}
+	for field := range parent.sensitive {
+		m.SetFieldSensitive(field)
+	}
+	for extended := range parent.extended {
+		m.extended[extended] = true
+	}
+}
+
+// clone returns a copy of the metadata
+func (m *Metadata) clone() *Metadata {
+	clone := newMetadata(m.structType)
+	clone.inherit(m)
+	return clone
+}
+
+// newMetadata returns empty metadata for the type structType
+func newMetadata(structType reflect.Type) *Metadata {
+	return &Metadata{constraints: []constraint.Constraint{}, structType: structType, extended: map[reflect.Type]bool{}}
+}
+
+// loadMetadata loads the metadata of loader. The metadata of the embedded
+// structs implementing ValidatorMetadataLoader is inherited first, then
+// loader.LoadValidatorMetadata is called unless it merely is the method
+// promoted from one of them, which adds the same constraints again
+func loadMetadata(loader ValidatorMetadataLoader, visited map[reflect.Type]bool) *Metadata {
+	structType := indirect(reflect.TypeOf(loader))
+	metadata := newMetadata(structType)
+	if visited[structType] {
+		return metadata
+	}
+	visited[structType] = true
+	defer delete(visited, structType)
+	parents := []*Metadata{}
+	if structType.Kind() == reflect.Struct {
+		value := reflect.Indirect(reflect.ValueOf(loader))
+		for i := 0; i < structType.NumField(); i++ {
+			field := structType.Field(i)
+			if parent, ok := embeddedLoader(value, field); ok {
+				inherited := loadMetadata(parent, visited)
+				parents = append(parents, inherited)
+				metadata.inherit(inherited.promote(structType, field))
+				metadata.extended[indirect(field.Type)] = true
+			}
+		}
+	}
+	loaded := metadata.clone()
+	loader.LoadValidatorMetadata(loaded)
+	loaded.own = added(metadata.constraints, loaded.constraints)
+	for _, parent := range parents {
+		if loaded.own != nil && sameConstraints(loaded.own, parent.own) {
+			metadata.own = parent.own
+			return metadata
+		}
+	}
+	return loaded
+}
+
+// added returns the constraints appended to before in after, or nil if
+// constraints of before were removed
+func added(before []constraint.Constraint, after []constraint.Constraint) []constraint.Constraint {
+	if len(after) < len(before) {
+		return nil
+	}
+	for i := range before {
+		if after[i] != before[i] {
+			return nil
+		}
+	}
+	return after[len(before):]
+}
+
+// sameConstraints returns true if a and b hold constraints of the same
+// types on the same fields
+func sameConstraints(a []constraint.Constraint, b []constraint.Constraint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if describeConstraint(a[i]) != describeConstraint(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// describeConstraint returns the field and the type of Constraint
+func describeConstraint(Constraint constraint.Constraint) string {
+	if fieldConstraint, ok := Constraint.(*constraint.FieldConstraint); ok {
+		return fieldConstraint.FieldName() + " " + describeConstraint(fieldConstraint.Constraint())
+	}
+	return reflect.TypeOf(Constraint).String()
+}
+
+// embeddedLoader returns the loader of the embedded field of value if the
+// field implements ValidatorMetadataLoader, a zero value being used when the
+// field is a nil pointer
+func embeddedLoader(value reflect.Value, field reflect.StructField) (ValidatorMetadataLoader, bool) {
+	if !field.Anonymous {
+		return nil, false
+	}
+	fieldValue := reflect.New(indirect(field.Type))
+	if embedded := reflect.Indirect(value.FieldByIndex(field.Index)); embedded.IsValid() && embedded.Kind() == reflect.Struct {
+		fieldValue.Elem().Set(embedded)
+	}
+	loader, ok := fieldValue.Interface().(ValidatorMetadataLoader)
+	return loader, ok
+}
+
+// indirect returns the type pointed to by t, t if it isn't a pointer
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// embeddedField returns the field of the struct type t embedding the type
+// parent, if any
+func embeddedField(t reflect.Type, parent reflect.Type) (reflect.StructField, bool) {
+	parent = indirect(parent)
+	if t == nil || t.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && indirect(field.Type) == parent {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// promote returns the metadata inherited by t from its embedded field.
+// Field names are kept when they are promoted to t, otherwise they are
+// prefixed with the name of the embedded field
+func (m *Metadata) promote(t reflect.Type, embedded reflect.StructField) *Metadata {
+	promoted := newMetadata(t)
+	for _, Constraint := range m.constraints {
+		if fieldConstraint, ok := Constraint.(*constraint.FieldConstraint); ok {
+			if name := promotedName(t, embedded, fieldConstraint.FieldName()); name != fieldConstraint.FieldName() {
+				Constraint = constraint.NewFieldConstraint(name, fieldConstraint.Constraint())
+			}
+		}
+		promoted.constraints = append(promoted.constraints, Constraint)
+	}
+	for field, label := range m.labels {
+		promoted.SetFieldLabel(promotedName(t, embedded, field), label)
+	}
+	for field := range m.sensitive {
+		promoted.SetFieldSensitive(promotedName(t, embedded, field))
+	}
+	promoted.extended = m.extended
+	return promoted
+}
+
+// promotedName returns the name in t of the field name of the embedded field
+func promotedName(t reflect.Type, embedded reflect.StructField, name string) string {
+	path, err := constraint.ParsePropertyPath(name)
+	if err != nil || len(path) == 0 || path[0].Kind != constraint.FieldElement {
+		return name
+	}
+	top := path[0].Name
+	inner, _ := indirect(embedded.Type).FieldByName(top)
+	outer, ok := t.FieldByName(top)
+	if ok && reflect.DeepEqual(outer.Index, append(append([]int{}, embedded.Index...), inner.Index...)) {
+		return name
+	}
+	return embedded.Name + "." + name
+}
+
+// compile checks the field constraints against t, sets their positions,
+// the labels and the sensitivity of the fields and returns a *MetadataError listing all the problems found
+func (m *Metadata) compile(t reflect.Type) error {
+	problems := []error{}
+	for position, Constraint := range m.constraints {
+		if fieldConstraint, ok := Constraint.(*constraint.FieldConstraint); ok {
+			fieldConstraint.SetPosition(position)
+			if err := fieldConstraint.CheckStruct(t); err != nil {
+				problems = append(problems, err)
+			}
+			if label, ok := m.labels[fieldConstraint.FieldName()]; ok {
+				fieldConstraint.SetLabel(label)
+			}
+			if m.sensitive[fieldConstraint.FieldName()] || taggedSensitive(fieldConstraint.PropertyPath(), t) {
+				fieldConstraint.SetSensitive(true)
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &MetadataError{typeString: t.String(), problems: problems}
+	}
+	return nil
+}
+
+// taggedSensitive returns true if one of the fields traversed by path in t
+// has the struct tag `sensitive:"true"`
+func taggedSensitive(path constraint.PropertyPath, t reflect.Type) bool {
+	for _, field := range path.StructFields(t) {
+		if field.Tag.Get("sensitive") == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// MetadataError is returned when the metadata of a type is invalid
+type MetadataError struct {
+	typeString string
+	problems   []error
+}
+
+// Error returns an error message listing all the problems
+func (me *MetadataError) Error() string {
+	messages := []string{}
+	for _, problem := range me.problems {
+		messages = append(messages, problem.Error())
+	}
+	return fmt.Sprintf("validator: invalid metadata for %s: %s", me.typeString, strings.Join(messages, "; "))
+}
+
+// Type returns the type whose metadata is invalid
+func (me *MetadataError) Type() string {
+	return me.typeString
+}
+
+// Problems returns the problems found in the metadata
+func (me *MetadataError) Problems() []error {
+	return me.problems
+}
+
+// ValidationError is the error returned by Check when a value is invalid
 type ValidationError struct {
+	violations ViolationList
 }
 
+// Error returns the messages of the violations
 func (ve *ValidationError) Error() string {
-	return "error"
+	return ve.violations.Error()
+}
+
+// Violations returns the violations
+func (ve *ValidationError) Violations() ViolationList {
+	return ve.violations
+}
+
+// Unwrap returns the violations so that errors.Is matches them against the
+// sentinel errors of the constraint package such as constraint.ErrBlank
+func (ve *ValidationError) Unwrap() []error {
+	return ve.violations.Unwrap()
+}
+
+// As sets target to the violations when target is a *ViolationList
+func (ve *ValidationError) As(target interface{}) bool {
+	if list, ok := target.(*ViolationList); ok {
+		*list = ve.violations
+		return true
+	}
+	return false
 }
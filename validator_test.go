@@ -4,7 +4,11 @@
 package validator_test
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/interactiv/expect"
 	"github.com/interactiv/validator"
@@ -22,6 +26,218 @@ func TestFactory(t *testing.T) {
 	e.Expect(len(Errors)).ToBeGreaterThan(0)
 }
 
+func TestClock(t *testing.T) {
+	e := expect.New(t)
+	clock := constraint.FixedClock(time.Date(2015, time.June, 15, 0, 0, 0, 0, time.UTC))
+	Validator := validator.New().SetClock(clock)
+	e.Expect(len(Validator.Validate(&Customer{BirthDate: "1997-06-15"}))).ToBe(0)
+	e.Expect(len(Validator.Validate(&Customer{BirthDate: "1997-06-16"}))).ToBe(1)
+}
+
+func TestUnwrapFields(t *testing.T) {
+	e := expect.New(t)
+	Validator := validator.New()
+	email := "john@example.com"
+	invalid := "john"
+	e.Expect(len(Validator.Validate(&Profile{}))).ToBe(1)
+	e.Expect(len(Validator.Validate(&Profile{Email: &email, Age: sql.NullInt64{Int64: 20, Valid: true}}))).ToBe(0)
+	Errors := Validator.Validate(&Profile{Email: &invalid, Website: &invalid, Age: sql.NullInt64{Int64: 200, Valid: true}})
+	e.Expect(len(Errors)).ToBe(3)
+	e.Expect(Errors[0].(constraint.FieldError).FieldName()).ToBe("Email")
+	e.Expect(Errors[1].(constraint.FieldError).FieldName()).ToBe("Website")
+	e.Expect(Errors[2].(constraint.FieldError).FieldName()).ToBe("Age")
+}
+
+func TestMetadataError(t *testing.T) {
+	e := expect.New(t)
+	Validator := validator.New()
+	Errors := Validator.Validate(&Broken{})
+	e.Expect(len(Errors)).ToBe(1)
+	var metadataError *validator.MetadataError
+	e.Expect(errors.As(Errors[0], &metadataError)).ToBe(true)
+	e.Expect(metadataError.Type()).ToBe("*validator_test.Broken")
+	e.Expect(len(metadataError.Problems())).ToBe(3)
+	e.Expect(metadataError.Problems()[0].Error()).ToBe("unknown field Nmae")
+	e.Expect(metadataError.Problems()[1].Error()).ToBe("field secret is unexported")
+	_, err := Validator.LoadMetadata(&Person{})
+	e.Expect(err == nil).ToBe(true)
+	err = constraint.NewFieldConstraint("Name", constraint.NotBlank()).Validate("not a struct")
+	e.Expect(err == nil).ToBe(false)
+	// metadata is loaded for each value
+	e.Expect(len(Validator.Validate(&Company{}))).ToBe(0)
+	e.Expect(len(Validator.Validate(&Company{IsCompany: true}))).ToBe(1)
+	e.Expect(len(Validator.Validate(&Company{}))).ToBe(0)
+}
+
+func TestInheritance(t *testing.T) {
+	e := expect.New(t)
+	Validator := validator.New()
+	// Article embeds BaseEntity without declaring its own metadata
+	Errors := Validator.Validate(&Article{BaseEntity: BaseEntity{CreatedBy: "john"}})
+	e.Expect(len(Errors)).ToBe(1)
+	e.Expect(Errors[0].(constraint.FieldError).FieldName()).ToBe("ID")
+	// Product adds its own metadata to the one of BaseEntity
+	Errors = Validator.Validate(&Product{BaseEntity: BaseEntity{ID: 1, CreatedBy: "john"}})
+	e.Expect(len(Errors)).ToBe(1)
+	e.Expect(Errors[0].(constraint.FieldError).FieldName()).ToBe("Name")
+	// Category overrides the ID constraints and shadows CreatedBy, extending
+	// the embedded BaseEntity explicitly doesn't inherit it twice
+	Errors = Validator.Validate(&Category{BaseEntity: &BaseEntity{ID: 0}, CreatedBy: 0})
+	e.Expect(len(Errors)).ToBe(1)
+	e.Expect(Errors[0].(constraint.FieldError).FieldName()).ToBe("BaseEntity.CreatedBy")
+	e.Expect(Errors[0].(constraint.FieldError).Label()).ToBe("Author")
+	// a nil embedded pointer has no value
+	Errors = Validator.Validate(&Category{})
+	e.Expect(len(Errors)).ToBe(1)
+	// Draft explicitly declares Article as its parent
+	Errors = Validator.Validate(&Draft{ID: 1, Title: ""})
+	e.Expect(len(Errors)).ToBe(1)
+	e.Expect(Errors[0].(constraint.FieldError).FieldName()).ToBe("Title")
+	// Tag declares its own metadata and inherits the one of BaseEntity
+	Errors = Validator.Validate(&Tag{})
+	e.Expect(len(Errors)).ToBe(3)
+	e.Expect(Errors[0].(constraint.FieldError).FieldName()).ToBe("ID")
+	e.Expect(Errors[1].(constraint.FieldError).FieldName()).ToBe("CreatedBy")
+	e.Expect(Errors[2].(constraint.FieldError).FieldName()).ToBe("Label")
+}
+
+func TestPropertyPaths(t *testing.T) {
+	e := expect.New(t)
+	Validator := validator.New()
+	Errors := Validator.Validate(&Invoice{
+		Address: Address{City: "Paris"},
+		Lines:   []InvoiceLine{{SKU: "A1"}, {SKU: ""}, {SKU: ""}},
+		Notes:   map[string]string{"fr": ""},
+	})
+	e.Expect(len(Errors)).ToBe(3)
+	e.Expect(Errors[0].(constraint.FieldError).FieldName()).ToBe("Lines[1].SKU")
+	e.Expect(Errors[1].(constraint.FieldError).FieldName()).ToBe("Lines[2].SKU")
+	e.Expect(Errors[2].(constraint.FieldError).FieldName()).ToBe("Notes[fr]")
+	Errors = Validator.Validate(&Invoice{Lines: []InvoiceLine{}})
+	e.Expect(len(Errors)).ToBe(3)
+	e.Expect(Errors[0].(constraint.FieldError).FieldName()).ToBe("Address.City")
+}
+
+func TestTagNamesAndLabels(t *testing.T) {
+	e := expect.New(t)
+	Errors := validator.New().SetTagName("json").Validate(&Signup{Contact: &Contact{}})
+	e.Expect(len(Errors)).ToBe(3)
+	e.Expect(Errors[0].(constraint.FieldError).FieldName()).ToBe("email_address")
+	e.Expect(Errors[0].Error()).ToBe("Email address should not be blank")
+	e.Expect(Errors[1].(constraint.FieldError).FieldName()).ToBe("Nickname")
+	e.Expect(Errors[1].Error()).ToBe("This value should not be blank")
+	e.Expect(Errors[2].(constraint.FieldError).FieldName()).ToBe("contact.phone_number")
+	Errors = validator.New().Validate(&Poll{Options: []string{"yes"}})
+	e.Expect(len(Errors)).ToBe(1)
+	e.Expect(Errors[0].Error()).ToBe("The options should contain 2 elements or more")
+	Errors = validator.New().Validate(&Signup{})
+	e.Expect(Errors[0].(constraint.FieldError).FieldName()).ToBe("EmailAddress")
+	e.Expect(Errors[2].(constraint.FieldError).FieldName()).ToBe("Contact.Phone")
+}
+
+func TestCheck(t *testing.T) {
+	e := expect.New(t)
+	Validator := validator.New()
+	e.Expect(Validator.Check(&Person{Name: "John Doe", IsMarried: true}) == nil).ToBe(true)
+	err := Validator.Check(&Person{Name: "", IsMarried: false})
+	e.Expect(errors.Is(err, constraint.ErrBlank)).ToBe(true)
+	e.Expect(errors.Is(err, constraint.ErrNotTrue)).ToBe(true)
+	e.Expect(errors.Is(err, constraint.ErrInvalidEmail)).ToBe(false)
+	var violations validator.ViolationList
+	e.Expect(errors.As(err, &violations)).ToBe(true)
+	e.Expect(len(violations)).ToBe(2)
+	e.Expect(violations[1].FieldName()).ToBe("IsMarried")
+	e.Expect(violations[0].Code()).ToBe(constraint.Code("IS_BLANK"))
+	e.Expect(violations[1].Code()).ToBe(constraint.ErrNotTrue)
+	var validationError *validator.ValidationError
+	e.Expect(errors.As(err, &validationError)).ToBe(true)
+	e.Expect(err.Error()).ToBe("Name: This value should not be blank\nIsMarried: This value should be true")
+	var metadataError *validator.MetadataError
+	e.Expect(errors.As(Validator.Check(&Broken{}), &metadataError)).ToBe(true)
+	// lookup failures aren't violations of the value
+	err = Validator.Check(&Member{Name: "John", Email: "john@example.com"})
+	var lookupError *constraint.LookupError
+	e.Expect(errors.As(err, &lookupError)).ToBe(true)
+	e.Expect(errors.Is(err, errConnectionRefused)).ToBe(true)
+	e.Expect(errors.As(err, &validationError)).ToBe(false)
+	e.Expect(errors.As(err, &violations)).ToBe(false)
+	// and are returned along with the violations
+	err = Validator.Check(&Member{Email: "john@example.com"})
+	e.Expect(errors.Is(err, errConnectionRefused)).ToBe(true)
+	e.Expect(errors.As(err, &violations)).ToBe(true)
+	e.Expect(len(violations)).ToBe(1)
+	e.Expect(violations[0].Code()).ToBe(constraint.ErrBlank)
+}
+
+func TestSeverity(t *testing.T) {
+	e := expect.New(t)
+	Validator := validator.New()
+	err := Validator.Check(&Registration{Username: "john", Password: "secret"})
+	var violations validator.ViolationList
+	e.Expect(errors.As(err, &violations)).ToBe(true)
+	e.Expect(len(violations.Errors())).ToBe(0)
+	e.Expect(len(violations.Warnings())).ToBe(1)
+	e.Expect(violations[0].Severity()).ToBe(constraint.SeverityWarning)
+	e.Expect(violations[0].Payload()["hint"]).ToBe("use a passphrase")
+	e.Expect(violations[0].Code()).ToBe(constraint.ErrTooShort)
+	Validator.SetIgnoreWarnings(true)
+	e.Expect(Validator.Check(&Registration{Username: "john", Password: "secret"}) == nil).ToBe(true)
+	e.Expect(len(Validator.Validate(&Registration{Username: "john", Password: "secret"}))).ToBe(1)
+	err = Validator.Check(&Registration{Password: "secret"})
+	e.Expect(errors.As(err, &violations)).ToBe(true)
+	e.Expect(len(violations)).ToBe(2)
+	e.Expect(violations.Errors()[0].FieldName()).ToBe("Username")
+	// a blank password violates the compound as an error
+	err = Validator.Check(&Credentials{Password: ""})
+	e.Expect(errors.As(err, &violations)).ToBe(true)
+	e.Expect(violations[0].Severity()).ToBe(constraint.SeverityError)
+	e.Expect(Validator.Check(&Credentials{Password: "secret"}) == nil).ToBe(true)
+	// typo suggestions don't block legitimate addresses
+	e.Expect(Validator.Check(&Newsletter{Email: "hans@yahoo.de"}) == nil).ToBe(true)
+	e.Expect(errors.As(validator.New().Check(&Newsletter{Email: "hans@yahoo.de"}), &violations)).ToBe(true)
+	e.Expect(violations[0].Payload()["suggestion"]).ToBe("yahoo.fr")
+}
+
+func TestSensitiveFields(t *testing.T) {
+	e := expect.New(t)
+	payment := &Payment{Email: "john", CardNumber: "4111", Password: "hunter2"}
+	Errors := validator.New().Validate(payment)
+	e.Expect(len(Errors)).ToBe(3)
+	e.Expect(Errors[0].Error()).ToBe("This value is not a valid email address")
+	e.Expect(Errors[0].(constraint.FieldError).InvalidValue()).ToBe("john")
+	e.Expect(Errors[1].Error()).ToBe(constraint.RedactedValue + " is not a valid card number")
+	e.Expect(Errors[1].(constraint.FieldError).InvalidValue()).ToBe(constraint.RedactedValue)
+	e.Expect(Errors[1].(constraint.FieldError).Sensitive()).ToBe(true)
+	e.Expect(Errors[2].(constraint.FieldError).InvalidValue()).ToBe(constraint.RedactedValue)
+	last4 := constraint.RedactorFunc(func(fieldName string, value interface{}) interface{} {
+		s := value.(string)
+		return "****" + s[len(s)-2:]
+	})
+	Errors = validator.New().SetRedactor(last4).Validate(payment)
+	e.Expect(Errors[1].Error()).ToBe("****11 is not a valid card number")
+	e.Expect(Errors[2].(constraint.FieldError).InvalidValue()).ToBe("****r2")
+}
+
+func TestViolationOrder(t *testing.T) {
+	e := expect.New(t)
+	invoice := &Invoice{Lines: make([]InvoiceLine, 12)}
+	var violations validator.ViolationList
+	e.Expect(errors.As(validator.New().Check(invoice), &violations)).ToBe(true)
+	shuffled := append(validator.ViolationList{}, violations...)
+	for i, j := 0, len(shuffled)-1; i < j; i, j = i+1, j-1 {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+	e.Expect(shuffled.Equal(violations)).ToBe(true)
+	e.Expect(shuffled[0].FieldName() == violations[0].FieldName()).ToBe(false)
+	shuffled.Sort()
+	for i := range violations {
+		e.Expect(shuffled[i].FieldName()).ToBe(violations[i].FieldName())
+	}
+	e.Expect(violations[1].FieldName()).ToBe("Lines")
+	e.Expect(violations[3].FieldName() + " " + violations[12].FieldName() + " " + violations[13].FieldName()).ToBe("Lines[1].SKU Lines[10].SKU Lines[11].SKU")
+	e.Expect(violations[:len(violations)-1].Equal(violations)).ToBe(false)
+}
+
 /********************************/
 /*         FIXTURES             */
 /********************************/
@@ -35,3 +251,259 @@ func (p *Person) LoadValidatorMetadata(metadata *validator.Metadata) {
 	metadata.AddFieldConstraint("Name", constraint.NotBlank()).
 		AddFieldConstraint("IsMarried", constraint.True())
 }
+
+func TestConstraintValidatorFactory(t *testing.T) {
+	e := expect.New(t)
+	taken := map[string]bool{"john@example.com": true}
+	factory := constraint.NewConstraintValidatorFactory().
+		Register("available_email", constraint.ConstraintValidatorFunc(func(value interface{}, c constraint.Constraint) error {
+			if taken[value.(string)] {
+				return errors.New(c.(*AvailableEmail).Message)
+			}
+			return nil
+		}))
+	Validator := validator.New().SetConstraintValidatorFactory(factory)
+	Errors := Validator.Validate(&Account{Email: "jane@example.com"})
+	e.Expect(len(Errors)).ToBe(0)
+	Errors = Validator.Validate(&Account{Email: "john@example.com"})
+	e.Expect(len(Errors)).ToBe(1)
+	e.Expect(Errors[0].Error()).ToBe("This email is already used")
+	// errors without code are violations with a generic code
+	err := Validator.Check(&Account{Email: "john@example.com"})
+	var validationError *validator.ValidationError
+	e.Expect(errors.As(err, &validationError)).ToBe(true)
+	e.Expect(validationError.Violations()[0].Code()).ToBe(constraint.ErrInvalid)
+	Errors = validator.New().Validate(&Account{Email: "jane@example.com"})
+	e.Expect(len(Errors)).ToBe(1)
+	e.Expect(errors.Is(Errors[0], constraint.ErrNoConstraintValidator)).ToBe(true)
+}
+
+type AvailableEmail struct {
+	constraint.Delegate
+	Message string
+}
+
+func (AvailableEmail) ValidatedBy() string {
+	return "available_email"
+}
+
+type Account struct {
+	Email string
+}
+
+func (a *Account) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Email", &AvailableEmail{Message: "This email is already used"})
+}
+
+var errConnectionRefused = errors.New("connection refused")
+
+type Member struct {
+	Name  string
+	Email string
+}
+
+func (m *Member) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Name", constraint.NotBlank()).
+		AddFieldConstraint("Email", constraint.Unique(constraint.LookupFunc(func(ctx context.Context, value interface{}) (bool, error) {
+			return false, errConnectionRefused
+		})))
+}
+
+type Customer struct {
+	BirthDate string
+}
+
+func (c *Customer) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("BirthDate", constraint.MinAge(18))
+}
+
+type Profile struct {
+	Email   *string
+	Website *string
+	Age     sql.NullInt64
+}
+
+func (p *Profile) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Email", constraint.Required()).
+		AddFieldConstraint("Email", constraint.Email()).
+		AddFieldConstraint("Website", constraint.URL()).
+		AddFieldConstraint("Age", constraint.Range(18, 130))
+}
+
+type Broken struct {
+	Name   string
+	Age    int
+	secret string
+}
+
+func (b *Broken) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Nmae", constraint.NotBlank()).
+		AddFieldConstraint("secret", constraint.NotBlank()).
+		AddFieldConstraint("Age", constraint.Email()).
+		AddFieldConstraint("Age", constraint.Range(0, 150)).
+		AddFieldConstraint("Name", constraint.Sequentially(constraint.NotBlank(), constraint.Length(1, 10)))
+}
+
+type Company struct {
+	IsCompany bool
+	VAT       string
+}
+
+func (c *Company) LoadValidatorMetadata(metadata *validator.Metadata) {
+	if c.IsCompany {
+		metadata.AddFieldConstraint("VAT", constraint.NotBlank())
+	}
+}
+
+type BaseEntity struct {
+	ID        int
+	CreatedBy string
+}
+
+func (b *BaseEntity) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("ID", constraint.GreaterThan(0)).
+		AddFieldConstraint("CreatedBy", constraint.Required()).
+		SetFieldLabel("CreatedBy", "Author")
+}
+
+type Article struct {
+	BaseEntity
+	Title string
+}
+
+type Product struct {
+	BaseEntity
+	Name string
+}
+
+func (p *Product) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Name", constraint.NotBlank())
+}
+
+type Category struct {
+	*BaseEntity
+	CreatedBy int
+}
+
+func (c Category) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.Extend(&BaseEntity{}).
+		RemoveFieldConstraints("ID").
+		AddFieldConstraint("ID", constraint.GreaterThanOrEqual(0))
+}
+
+type Tag struct {
+	BaseEntity
+	Label string
+}
+
+func (t *Tag) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Label", constraint.NotBlank())
+}
+
+type Draft struct {
+	ID        int
+	CreatedBy string
+	Title     string
+}
+
+func (d *Draft) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.Extend(&Article{}).
+		RemoveFieldConstraints("CreatedBy").
+		AddFieldConstraint("Title", constraint.NotBlank())
+}
+
+type Address struct {
+	City string
+}
+
+type InvoiceLine struct {
+	SKU string
+}
+
+type Invoice struct {
+	Address Address
+	Lines   []InvoiceLine
+	Notes   map[string]string
+}
+
+func (i *Invoice) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Address.City", constraint.NotBlank()).
+		AddFieldConstraint("Lines", constraint.Count(1, 10)).
+		AddFieldConstraint("Lines[*].SKU", constraint.NotBlank()).
+		AddFieldConstraint("Notes[fr]", constraint.NotBlank())
+}
+
+type Contact struct {
+	Phone string `json:"phone_number,omitempty"`
+}
+
+type Signup struct {
+	EmailAddress string   `json:"email_address"`
+	Nickname     string   `json:"-"`
+	Contact      *Contact `json:"contact"`
+}
+
+func (s *Signup) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("EmailAddress", constraint.NotBlank()).
+		AddFieldConstraint("Nickname", constraint.NotBlank()).
+		AddFieldConstraint("Contact.Phone", constraint.NotBlank()).
+		SetFieldLabel("EmailAddress", "Email address")
+}
+
+type Poll struct {
+	Options []string
+}
+
+func (p *Poll) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Options", constraint.Count(2, 10)).
+		SetFieldLabel("Options", "The options")
+}
+
+type Registration struct {
+	Username string
+	Password string
+}
+
+func (r *Registration) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Username", constraint.NotBlank()).
+		AddFieldConstraint("Password", constraint.Warning(constraint.Length(12, 128)).
+			SetPayload(map[string]interface{}{"hint": "use a passphrase"}))
+}
+
+type Credentials struct {
+	Password string
+}
+
+func (c *Credentials) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Password", constraint.NewCompound("Password", constraint.Warning(constraint.Length(12, 128)), constraint.NotBlank()))
+}
+
+type Newsletter struct {
+	Email string
+}
+
+func (n *Newsletter) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Email", constraint.Email().SetSuggestions(constraint.CommonEmailDomains))
+}
+
+type Payment struct {
+	Email      string
+	CardNumber string
+	Password   string `sensitive:"true"`
+}
+
+func (p *Payment) LoadValidatorMetadata(metadata *validator.Metadata) {
+	metadata.AddFieldConstraint("Email", constraint.Email()).
+		AddFieldConstraint("CardNumber", CardNumber{}).
+		AddFieldConstraint("Password", constraint.Length(12, 128)).
+		SetFieldSensitive("CardNumber")
+}
+
+type CardNumber struct{}
+
+func (CardNumber) Validate(value interface{}) error {
+	if len(value.(string)) < 13 {
+		return constraint.NewError(constraint.Code("INVALID_CARD_NUMBER"), constraint.ValuePlaceholder+" is not a valid card number")
+	}
+	return nil
+}
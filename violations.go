@@ -0,0 +1,96 @@
+// Copyrights 2015 mparaiso <mparaiso@online.fr>
+// License MIT
+
+package validator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/interactiv/validator/constraint"
+)
+
+// ViolationList is the list of the constraint violations of a validated value
+type ViolationList []constraint.FieldError
+
+// Error returns the messages of the violations
+func (vl ViolationList) Error() string {
+	messages := []string{}
+	for _, violation := range vl {
+		messages = append(messages, violation.FieldName()+": "+violation.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the violations so that errors.Is matches them against the
+// sentinel errors of the constraint package such as constraint.ErrBlank
+func (vl ViolationList) Unwrap() []error {
+	errs := make([]error, len(vl))
+	for i, violation := range vl {
+		errs[i] = violation
+	}
+	return errs
+}
+
+// Sort sorts the violations by the declaration order of their field
+// constraints, then by property path and message, and returns the list
+func (vl ViolationList) Sort() ViolationList {
+	sort.SliceStable(vl, func(i, j int) bool {
+		return vl[i].Compare(vl[j]) < 0
+	})
+	return vl
+}
+
+// Equal returns true if vl and other hold equal violations, whatever
+// their order
+func (vl ViolationList) Equal(other ViolationList) bool {
+	if len(vl) != len(other) {
+		return false
+	}
+	a := append(ViolationList{}, vl...).Sort()
+	b := append(ViolationList{}, other...).Sort()
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// BySeverity returns the violations of severity
+func (vl ViolationList) BySeverity(severity constraint.Severity) ViolationList {
+	violations := ViolationList{}
+	for _, violation := range vl {
+		if violation.Severity() == severity {
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// Errors returns the blocking violations, those of severity error
+func (vl ViolationList) Errors() ViolationList {
+	return vl.BySeverity(constraint.SeverityError)
+}
+
+// Warnings returns the violations of severity warning
+func (vl ViolationList) Warnings() ViolationList {
+	return vl.BySeverity(constraint.SeverityWarning)
+}
+
+// newViolationList returns the violations found in errs and the other errors.
+// Field errors reporting system failures, such as the *LookupError of a
+// failing Lookup, aren't violations of the value and are returned with the
+// other errors, see constraint.IsSystemError
+func newViolationList(errs []error) (ViolationList, []error) {
+	violations := ViolationList{}
+	others := []error{}
+	for _, err := range errs {
+		if violation, ok := err.(constraint.FieldError); ok && !constraint.IsSystemError(violation) {
+			violations = append(violations, violation)
+		} else {
+			others = append(others, err)
+		}
+	}
+	return violations, others
+}